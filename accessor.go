@@ -0,0 +1,92 @@
+package mysqlib
+
+import (
+	"reflect"
+)
+
+// Accessor 模型访问器接口
+// 可以使用 cmd/mysqlib-gen 工具为模型生成此接口的实现代码，例如在模型所在的文件中加入：
+// //go:generate go run github.com/dxvgef/mysqlib/cmd/mysqlib-gen -type User
+// 实现了此接口的模型在构建语句和赋值记录集时不再通过反射读写字段，没有实现此接口的模型仍然使用反射
+// SQLColumns()返回的字段与模型的字段不一致时（修改了模型但没有重新生成代码），视为访问器已过期，仍然使用反射
+type Accessor interface {
+	SQLColumns() []string                 //返回模型中所有标记了的字段名
+	SQLValue(column string) interface{}   //返回字段的值
+	SQLPointer(column string) interface{} //返回字段的内存地址，用于Scan赋值
+}
+
+var accessorType = reflect.TypeOf((*Accessor)(nil)).Elem()
+
+//模型是否实现了访问器接口，并且访问器的字段与模型的字段一致
+func validAccessor(rType reflect.Type, columns []string) bool {
+	if reflect.PtrTo(rType).Implements(accessorType) == false {
+		return false
+	}
+	accessorColumns := reflect.New(rType).Interface().(Accessor).SQLColumns()
+	if len(accessorColumns) != len(columns) {
+		return false
+	}
+	exists := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		exists[column] = true
+	}
+	for _, column := range accessorColumns {
+		if exists[column] == false {
+			return false
+		}
+	}
+	return true
+}
+
+//得到模型实例的访问器，row必须是可寻址的结构体，访问器已过期时返回nil
+func (sess *Session) toAccessor(row reflect.Value) Accessor {
+	if sess.modelInfo.accessor == false || row.CanAddr() == false {
+		return nil
+	}
+	accessor, _ := row.Addr().Interface().(Accessor)
+	return accessor
+}
+
+//取得一行模型实例中指定字段的值
+func (sess *Session) rowValues(row reflect.Value, columns []string) []interface{} {
+	values := make([]interface{}, len(columns))
+	//优先使用生成的访问器
+	if accessor := sess.toAccessor(row); accessor != nil {
+		for i, column := range columns {
+			values[i] = accessor.SQLValue(column)
+		}
+		return values
+	}
+	//没有访问器则使用反射
+	for i, column := range columns {
+		values[i] = row.FieldByIndex(sess.modelInfo.fields[column].index).Interface()
+	}
+	return values
+}
+
+//取得一行模型实例中指定字段的内存地址，用于Scan赋值
+func (sess *Session) rowPointers(row reflect.Value, columns []string) []interface{} {
+	pointers := make([]interface{}, len(columns))
+	//优先使用生成的访问器
+	if accessor := sess.toAccessor(row); accessor != nil {
+		for i, column := range columns {
+			pointers[i] = accessor.SQLPointer(column)
+			//不属于模型的字段，读取后丢弃
+			if pointers[i] == nil {
+				pointers[i] = new(interface{})
+			}
+		}
+		return pointers
+	}
+	//没有访问器则使用反射
+	for i, column := range columns {
+		field, ok := sess.modelInfo.fields[column]
+		//不属于模型的字段，读取后丢弃
+		if ok == false {
+			pointers[i] = new(interface{})
+			continue
+		}
+		pointers[i] = row.FieldByIndex(field.index).Addr().Interface()
+	}
+	return pointers
+}
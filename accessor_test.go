@@ -0,0 +1,166 @@
+package mysqlib
+
+import (
+	"reflect"
+	"testing"
+)
+
+//go:generate go run ./cmd/mysqlib-gen -type benchAccessorUser
+
+// 使用反射读写字段的模型
+type benchReflectUser struct {
+	tableName struct{} `sql:"user"`
+	ID        int64    `sql:"id"`
+	Username  string   `sql:"username"`
+	Password  string   `sql:"password"`
+	Email     string   `sql:"email"`
+	Age       int      `sql:"age"`
+}
+
+// 使用生成的访问器读写字段的模型
+type benchAccessorUser struct {
+	tableName struct{} `sql:"user"`
+	ID        int64    `sql:"id"`
+	Username  string   `sql:"username"`
+	Password  string   `sql:"password"`
+	Email     string   `sql:"email"`
+	Age       int      `sql:"age"`
+}
+
+// TestAccessor 测试生成的访问器与反射得到的结果一致
+func TestAccessor(t *testing.T) {
+	builder := New()
+	reflectUser := benchReflectUser{ID: 1, Username: "dxvgef", Password: "123456", Email: "dxvgef@example.com", Age: 18}
+	accessorUser := benchAccessorUser{ID: 1, Username: "dxvgef", Password: "123456", Email: "dxvgef@example.com", Age: 18}
+
+	reflectSess, err := builder.Insert(&reflectUser).Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	accessorSess, err := builder.Insert(&accessorUser).Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if reflectSess.GetStmt() != accessorSess.GetStmt() {
+		t.Error("构建的SQL语句不一致：", reflectSess.GetStmt(), accessorSess.GetStmt())
+	}
+	if reflect.DeepEqual(reflectSess.GetValues(), accessorSess.GetValues()) == false {
+		t.Error("构建的参数不一致：", reflectSess.GetValues(), accessorSess.GetValues())
+	}
+	t.Log("构建的SQL语句：", accessorSess.GetStmt())
+	t.Log(accessorSess.GetValues())
+}
+
+// 访问器过期的模型，新增的email字段没有重新生成访问器代码
+type staleAccessorUser struct {
+	tableName struct{} `sql:"user"`
+	ID        int64    `sql:"id"`
+	Username  string   `sql:"username"`
+	Email     string   `sql:"email"`
+}
+
+func (m *staleAccessorUser) SQLColumns() []string {
+	return []string{"id", "username"}
+}
+
+func (m *staleAccessorUser) SQLValue(column string) interface{} {
+	switch column {
+	case "id":
+		return m.ID
+	case "username":
+		return m.Username
+	}
+	return nil
+}
+
+func (m *staleAccessorUser) SQLPointer(column string) interface{} {
+	switch column {
+	case "id":
+		return &m.ID
+	case "username":
+		return &m.Username
+	}
+	return nil
+}
+
+// TestStaleAccessor 测试访问器的字段与模型不一致时使用反射读写字段
+func TestStaleAccessor(t *testing.T) {
+	user := staleAccessorUser{ID: 1, Username: "dxvgef", Email: "dxvgef@example.com"}
+	sqlSess, err := New().Insert(&user).Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if sqlSess.modelInfo.accessor == true {
+		t.Error("没有发现访问器已过期")
+	}
+	if reflect.DeepEqual(sqlSess.GetValues(), []interface{}{int64(1), "dxvgef", "dxvgef@example.com"}) == false {
+		t.Error("构建的参数不正确：", sqlSess.GetValues())
+	}
+
+	var users []staleAccessorUser
+	sess, err := New().Select(&users).Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	row := reflect.New(sess.modelValue.rType).Elem()
+	pointers := sess.rowPointers(row, []string{"email"})
+	if pointers[0] != row.Field(3).Addr().Interface() {
+		t.Error("email字段的Scan目标不是模型的字段")
+	}
+
+	// 与模型一致的访问器仍然使用
+	if accessorSess, _ := New().Select(&[]benchAccessorUser{}).Build(false); accessorSess.modelInfo.accessor == false {
+		t.Error("没有使用生成的访问器")
+	}
+}
+
+func BenchmarkInsertReflect(b *testing.B) {
+	builder := New()
+	user := benchReflectUser{ID: 1, Username: "dxvgef", Password: "123456"}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := builder.Insert(&user).Build(false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkInsertAccessor(b *testing.B) {
+	builder := New()
+	user := benchAccessorUser{ID: 1, Username: "dxvgef", Password: "123456"}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := builder.Insert(&user).Build(false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// 模拟ScanModelSlice中每一行记录创建模型实例并取得Scan目标的过程
+func benchmarkScanTargets(b *testing.B, users interface{}) {
+	sess, err := New().Select(users).Build(false)
+	if err != nil {
+		b.Fatal(err)
+	}
+	columns := sess.fieldKeys()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		newRow := reflect.New(sess.modelValue.rType).Elem()
+		sess.rowPointers(newRow, columns)
+	}
+}
+
+func BenchmarkScanReflect(b *testing.B) {
+	var users []benchReflectUser
+	benchmarkScanTargets(b, &users)
+}
+
+func BenchmarkScanAccessor(b *testing.B) {
+	var users []benchAccessorUser
+	benchmarkScanTargets(b, &users)
+}
@@ -0,0 +1,29 @@
+package mysqlib
+
+import (
+	"errors"
+	"reflect"
+)
+
+// SetInsertID 将执行本条语句后得到的LastInsertId()写回到模型中标记了auto_increment的字段，例如`sql:"id,auto_increment"`
+// 批量INSERT时MySQL返回的是第一条记录的自增ID，其余记录按顺序递增赋值，
+// 这要求数据库保证同一条语句生成的自增ID是连续的（innodb_autoinc_lock_mode为0或1时可以保证）
+func (batch *Batch) SetInsertID(id int64) error {
+	info := batch.sess.modelInfo
+	if info.autoIncr == "" {
+		return errors.New("模型中没有标记`auto_increment`的字段")
+	}
+	index := info.fields[info.autoIncr].index
+	for i, row := range batch.rows {
+		field := row.FieldByIndex(index)
+		switch field.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			field.SetInt(id + int64(i))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			field.SetUint(uint64(id + int64(i)))
+		default:
+			return errors.New("`auto_increment`字段必须是整数类型")
+		}
+	}
+	return nil
+}
@@ -1,9 +1,16 @@
 package mysqlib
 
+import (
+	"sync"
+	"time"
+)
+
 // Instance 构建器实例
 type Instance struct {
-	options    *Options              //配置
-	modelCache map[string]*modelInfo //模型结构缓存
+	options    *Options                               //配置
+	modelCache map[string]*modelInfo                  //模型结构缓存
+	trackMu    sync.Mutex                             //tracked的锁
+	tracked    map[interface{}]map[string]interface{} //Track()保存的模型快照，key是模型的内存地址
 }
 
 // Options 构建器实例配置选项
@@ -11,6 +18,13 @@ type Options struct {
 	TagName           string //标记名
 	TableNameField    string //表名字段名
 	DisableModelCache bool   //禁用模型缓存（默认开启）
+	MaxBatchRows      int    //批量INSERT时每条语句最多包含的记录数（默认只受占位符数量上限限制）
+	CursorKey         []byte //游标分页时给游标签名的密钥，防止游标被篡改
+	StrictScan        bool   //Scan时结果集中有模型里没有的字段则返回错误（默认忽略这些字段）
+
+	//以下配置只影响Build(true)构建的语句，需要与数据库及驱动的设置一致
+	NoBackslashEscapes bool           //数据库启用了NO_BACKSLASH_ESCAPES模式，字符串中的'转义成''而不是\'，LIKE的ESCAPE子句也按此模式书写反斜杠
+	Location           *time.Location //时间值转换到的时区，与驱动的loc参数一致（默认UTC）
 }
 
 // New 实例化
@@ -52,6 +66,16 @@ func (instance *Instance) Insert(m interface{}) *Session {
 	return &sess
 }
 
+// Replace 设置会话的行为是REPLACE操作，用法与Insert()相同
+func (instance *Instance) Replace(m interface{}) *Session {
+	//创建会话
+	var sess Session
+	sess.builder = instance   //存入构建器指针
+	sess.modelValue.Value = m //存入模型实例
+	sess.stmt.action = "REPLACE"
+	return &sess
+}
+
 // Update 设置会话的行为是UPDATE操作
 func (instance *Instance) Update(m interface{}) *Session {
 	//创建会话
@@ -0,0 +1,285 @@
+// mysqlib-gen 为模型生成mysqlib.Accessor接口的实现代码，使构建语句和赋值记录集时不再需要反射
+//
+// 在模型所在的文件中加入以下注释，然后执行go generate：
+// //go:generate go run github.com/dxvgef/mysqlib/cmd/mysqlib-gen -type User,Post
+//
+// 生成的代码会写入到同目录下的<文件名>_mysqlib.go文件中，如果模型定义在_test.go文件中，
+// 则写入到<文件名>_mysqlib_test.go文件中
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+//模型信息
+type model struct {
+	name   string   //结构体名称
+	fields []*field //标记了的字段
+}
+
+//模型里的字段信息
+type field struct {
+	varName string //模型变量名
+	sqlName string //数据表字段名
+}
+
+func main() {
+	var (
+		typeNames      = flag.String("type", "", "要生成代码的结构体名称，多个用逗号分隔，为空则处理文件中所有标记了字段的结构体")
+		tagName        = flag.String("tag", "sql", "标记名，必须与mysqlib.Options.TagName一致")
+		tableNameField = flag.String("table", "tableName", "表名字段名，必须与mysqlib.Options.TableNameField一致")
+		output         = flag.String("output", "", "输出文件名，默认为<文件名>_mysqlib.go")
+	)
+	flag.Parse()
+	log.SetFlags(0)
+	log.SetPrefix("mysqlib-gen: ")
+
+	//go generate执行时会设置GOFILE环境变量，也可以用参数指定源文件
+	fileName := os.Getenv("GOFILE")
+	if flag.NArg() > 0 {
+		fileName = flag.Arg(0)
+	}
+	if fileName == "" {
+		log.Fatal("没有指定源文件")
+	}
+
+	var types []string
+	if *typeNames != "" {
+		types = strings.Split(*typeNames, ",")
+	}
+
+	src, err := generate(fileName, types, *tagName, *tableNameField)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *output == "" {
+		*output = outputName(fileName)
+	}
+	if err = os.WriteFile(*output, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+//根据源文件名得到输出文件名
+func outputName(fileName string) string {
+	if strings.HasSuffix(fileName, "_test.go") {
+		return strings.TrimSuffix(fileName, "_test.go") + "_mysqlib_test.go"
+	}
+	return strings.TrimSuffix(fileName, ".go") + "_mysqlib.go"
+}
+
+//解析源文件并生成代码
+func generate(fileName string, types []string, tagName, tableNameField string) ([]byte, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), fileName, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	//嵌套的结构体可能定义在同一个包的其它文件中
+	pkgFiles, err := parsePackage(fileName, file.Name.Name)
+	if err != nil {
+		return nil, err
+	}
+	var pkgModels []*model
+	for _, f := range pkgFiles {
+		pkgModels = append(pkgModels, parseModels(f, tagName, tableNameField)...)
+	}
+
+	models := parseModels(file, tagName, tableNameField)
+	models = removeNested(file, models, pkgModels)
+
+	//如果指定了结构体名称，则只处理指定的结构体，且必须都能找到
+	if len(types) > 0 {
+		var selected []*model
+		for _, name := range types {
+			name = strings.TrimSpace(name)
+			var found *model
+			for _, m := range models {
+				if m.name == name {
+					found = m
+					break
+				}
+			}
+			if found == nil {
+				return nil, fmt.Errorf("在%s中没有找到标记了字段的结构体`%s`", fileName, name)
+			}
+			selected = append(selected, found)
+		}
+		models = selected
+	}
+	if len(models) == 0 {
+		return nil, errors.New("没有找到需要生成代码的结构体")
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by mysqlib-gen. DO NOT EDIT.\n\n")
+	buf.WriteString("package " + file.Name.Name + "\n")
+	for _, m := range models {
+		writeModel(&buf, m)
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+//从语法树中找出所有标记了字段的结构体
+func parseModels(file *ast.File, tagName, tableNameField string) []*model {
+	var models []*model
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if ok == false || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if ok == false {
+				continue
+			}
+			m := model{
+				name: typeSpec.Name.Name,
+			}
+			for _, f := range structType.Fields.List {
+				//跳过没有标记和匿名的字段
+				if f.Tag == nil || len(f.Names) == 0 {
+					continue
+				}
+				tag, err := strconv.Unquote(f.Tag.Value)
+				if err != nil {
+					continue
+				}
+				sqlName := reflect.StructTag(tag).Get(tagName)
+				if sqlName == "" {
+					continue
+				}
+				//去掉标记中的选项，例如`sql:"id,auto_increment"`
+				sqlName = strings.TrimSpace(strings.Split(sqlName, ",")[0])
+				for _, name := range f.Names {
+					//跳过标记表名的字段
+					if name.Name == tableNameField {
+						continue
+					}
+					m.fields = append(m.fields, &field{
+						varName: name.Name,
+						sqlName: sqlName,
+					})
+				}
+			}
+			if len(m.fields) > 0 {
+				models = append(models, &m)
+			}
+		}
+	}
+	return models
+}
+
+//解析源文件所在目录中属于同一个包的所有文件，源文件是_test.go文件时也包括测试文件
+func parsePackage(fileName, pkgName string) ([]*ast.File, error) {
+	dir := filepath.Dir(fileName)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	withTest := strings.HasSuffix(fileName, "_test.go")
+	var files []*ast.File
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() == true || strings.HasSuffix(name, ".go") == false {
+			continue
+		}
+		if withTest == false && strings.HasSuffix(name, "_test.go") == true {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		if file.Name.Name == pkgName {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+//去掉模型中嵌套的结构体字段，例如`Profile *Profile `sql:"p"``，这类字段由mysqlib通过反射赋值
+//pkgModels是包中所有标记了字段的结构体，嵌套的结构体可以定义在包中的任何文件里
+func removeNested(file *ast.File, models []*model, pkgModels []*model) []*model {
+	//包中所有标记了字段的结构体名称
+	names := make(map[string]bool)
+	for _, m := range pkgModels {
+		names[m.name] = true
+	}
+	for _, m := range models {
+		//字段类型是包中的其它模型
+		nested := make(map[string]bool)
+		ast.Inspect(file, func(node ast.Node) bool {
+			typeSpec, ok := node.(*ast.TypeSpec)
+			if ok == false || typeSpec.Name.Name != m.name {
+				return true
+			}
+			for _, f := range typeSpec.Type.(*ast.StructType).Fields.List {
+				expr := f.Type
+				if star, ok := expr.(*ast.StarExpr); ok == true {
+					expr = star.X
+				}
+				if ident, ok := expr.(*ast.Ident); ok == true && names[ident.Name] == true {
+					for _, name := range f.Names {
+						nested[name.Name] = true
+					}
+				}
+			}
+			return false
+		})
+		var fields []*field
+		for _, f := range m.fields {
+			if nested[f.varName] == false {
+				fields = append(fields, f)
+			}
+		}
+		m.fields = fields
+	}
+	return models
+}
+
+//写入一个模型的访问器代码
+func writeModel(buf *bytes.Buffer, m *model) {
+	fmt.Fprintf(buf, "\n// SQLColumns 返回模型中所有标记了的字段名\n")
+	fmt.Fprintf(buf, "func (m *%s) SQLColumns() []string {\n", m.name)
+	buf.WriteString("return []string{")
+	for k, f := range m.fields {
+		if k > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(strconv.Quote(f.sqlName))
+	}
+	buf.WriteString("}\n}\n")
+
+	fmt.Fprintf(buf, "\n// SQLValue 返回字段的值\n")
+	fmt.Fprintf(buf, "func (m *%s) SQLValue(column string) interface{} {\n", m.name)
+	buf.WriteString("switch column {\n")
+	for _, f := range m.fields {
+		fmt.Fprintf(buf, "case %s:\nreturn m.%s\n", strconv.Quote(f.sqlName), f.varName)
+	}
+	buf.WriteString("}\nreturn nil\n}\n")
+
+	fmt.Fprintf(buf, "\n// SQLPointer 返回字段的内存地址，用于Scan赋值\n")
+	fmt.Fprintf(buf, "func (m *%s) SQLPointer(column string) interface{} {\n", m.name)
+	buf.WriteString("switch column {\n")
+	for _, f := range m.fields {
+		fmt.Fprintf(buf, "case %s:\nreturn &m.%s\n", strconv.Quote(f.sqlName), f.varName)
+	}
+	buf.WriteString("}\nreturn nil\n}\n")
+}
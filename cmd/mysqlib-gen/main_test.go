@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestGenerateNested 测试嵌套的结构体定义在同一个包的其它文件中时，不会生成嵌套字段的代码
+func TestGenerateNested(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"user.go": "package model\n\n" +
+			"type User struct {\n" +
+			"\ttableName struct{} `sql:\"user\"`\n" +
+			"\tID int64 `sql:\"id\"`\n" +
+			"\tProfile *Profile `sql:\"p\"`\n" +
+			"}\n",
+		"profile.go": "package model\n\n" +
+			"type Profile struct {\n" +
+			"\tAvatar string `sql:\"avatar\"`\n" +
+			"}\n",
+		//其它包的文件不影响生成的代码
+		"other_test.go": "package model_test\n\n" +
+			"type User struct {\n" +
+			"\tName string `sql:\"name\"`\n" +
+			"}\n",
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	src, err := generate(filepath.Join(dir, "user.go"), []string{"User"}, "sql", "tableName")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := string(src)
+	if strings.Contains(code, `"p"`) == true || strings.Contains(code, "m.Profile") == true {
+		t.Error("生成了嵌套结构体字段的代码：\n", code)
+	}
+	if strings.Contains(code, `return []string{"id"}`) == false {
+		t.Error("SQLColumns()返回的字段不正确：\n", code)
+	}
+}
@@ -0,0 +1,430 @@
+package mysqlib
+
+import (
+	"strings"
+)
+
+// Cond 条件组，用于构建有嵌套优先级的WHERE/HAVING条件，整组条件会用括号包裹
+// 可以在WhereGroup()的回调函数中使用，也可以用Where()、And()、Or()、Not()组合后传给WhereCond()
+type Cond struct {
+	conds []*whereCond //组内的条件
+}
+
+// Where 创建一个只有一个条件的条件组，用于组合And()、Or()、Not()
+func Where(field, operator string, value interface{}) *Cond {
+	var cond Cond
+	return cond.Where(field, operator, value)
+}
+
+// And 将多个条件组用AND连接成一个条件组，为nil或没有条件的条件组会被跳过
+func And(conds ...*Cond) *Cond {
+	return joinConds("AND", conds)
+}
+
+// Or 将多个条件组用OR连接成一个条件组，为nil或没有条件的条件组会被跳过
+func Or(conds ...*Cond) *Cond {
+	return joinConds("OR", conds)
+}
+
+// Not 将条件组取反，渲染为NOT (...)，cond为nil时返回没有条件的条件组
+func Not(cond *Cond) *Cond {
+	var result Cond
+	if cond == nil {
+		return &result
+	}
+	result.conds = append(result.conds, &whereCond{
+		union:    "AND",
+		group:    true,
+		children: cond.conds,
+		not:      true,
+	})
+	return &result
+}
+
+//将多个条件组用指定的连接符连接成一个条件组
+func joinConds(union string, conds []*Cond) *Cond {
+	var result Cond
+	for _, v := range conds {
+		if v == nil || len(v.conds) == 0 {
+			continue
+		}
+		result.conds = append(result.conds, newGroupCond(union, v))
+	}
+	return &result
+}
+
+// Where 设置AND条件，作用跟AndWhere()一样
+func (cond *Cond) Where(field, operator string, value interface{}) *Cond {
+	return cond.whereHandle("AND", field, operator, value)
+}
+
+// AndWhere 设置AND条件
+func (cond *Cond) AndWhere(field, operator string, value interface{}) *Cond {
+	return cond.whereHandle("AND", field, operator, value)
+}
+
+// OrWhere 设置OR条件
+func (cond *Cond) OrWhere(field, operator string, value interface{}) *Cond {
+	return cond.whereHandle("OR", field, operator, value)
+}
+
+// WhereRaw 传入原生条件语句
+// 语句中的?占位符依次对应args，不要把外部输入直接拼接到语句中
+func (cond *Cond) WhereRaw(stmt string, args ...interface{}) *Cond {
+	return cond.rawHandle("AND", stmt, args)
+}
+
+// AndWhereRaw 传入原生AND条件语句
+// 语句中的?占位符依次对应args，不要把外部输入直接拼接到语句中
+func (cond *Cond) AndWhereRaw(stmt string, args ...interface{}) *Cond {
+	return cond.rawHandle("AND", stmt, args)
+}
+
+// OrWhereRaw 传入原生OR条件语句
+// 语句中的?占位符依次对应args，不要把外部输入直接拼接到语句中
+func (cond *Cond) OrWhereRaw(stmt string, args ...interface{}) *Cond {
+	return cond.rawHandle("OR", stmt, args)
+}
+
+// WhereIn 传入IN条件，作用和AndWhereIn()一样
+func (cond *Cond) WhereIn(field string, value interface{}) *Cond {
+	return cond.whereHandle("AND", field, "IN", value)
+}
+
+// AndWhereIn 传入AND IN条件
+func (cond *Cond) AndWhereIn(field string, value interface{}) *Cond {
+	return cond.whereHandle("AND", field, "IN", value)
+}
+
+// OrWhereIn 传入OR IN条件
+func (cond *Cond) OrWhereIn(field string, value interface{}) *Cond {
+	return cond.whereHandle("OR", field, "IN", value)
+}
+
+// WhereNotIn 传入NOT IN条件，作用和AndWhereNotIn()一样
+func (cond *Cond) WhereNotIn(field string, value interface{}) *Cond {
+	return cond.whereHandle("AND", field, "NOT IN", value)
+}
+
+// AndWhereNotIn 传入AND NOT IN条件
+func (cond *Cond) AndWhereNotIn(field string, value interface{}) *Cond {
+	return cond.whereHandle("AND", field, "NOT IN", value)
+}
+
+// OrWhereNotIn 传入OR NOT IN条件
+func (cond *Cond) OrWhereNotIn(field string, value interface{}) *Cond {
+	return cond.whereHandle("OR", field, "NOT IN", value)
+}
+
+// WhereGroup 设置AND条件组，作用和AndWhereGroup()一样，回调函数中设置的条件会用括号包裹
+func (cond *Cond) WhereGroup(fn func(*Cond)) *Cond {
+	return cond.groupHandle("AND", fn)
+}
+
+// AndWhereGroup 设置AND条件组
+func (cond *Cond) AndWhereGroup(fn func(*Cond)) *Cond {
+	return cond.groupHandle("AND", fn)
+}
+
+// OrWhereGroup 设置OR条件组
+func (cond *Cond) OrWhereGroup(fn func(*Cond)) *Cond {
+	return cond.groupHandle("OR", fn)
+}
+
+// WhereCond 设置AND条件组，作用和AndWhereCond()一样
+func (cond *Cond) WhereCond(group *Cond) *Cond {
+	return cond.condHandle("AND", group)
+}
+
+// AndWhereCond 设置AND条件组
+func (cond *Cond) AndWhereCond(group *Cond) *Cond {
+	return cond.condHandle("AND", group)
+}
+
+// OrWhereCond 设置OR条件组
+func (cond *Cond) OrWhereCond(group *Cond) *Cond {
+	return cond.condHandle("OR", group)
+}
+
+func (cond *Cond) whereHandle(union, field, operator string, value interface{}) *Cond {
+	cond.conds = append(cond.conds, newWhereCond(union, field, operator, value))
+	return cond
+}
+
+func (cond *Cond) rawHandle(union, stmt string, args []interface{}) *Cond {
+	cond.conds = append(cond.conds, &whereCond{union: union, raw: &rawExpr{stmt: stmt, args: args}})
+	return cond
+}
+
+func (cond *Cond) groupHandle(union string, fn func(*Cond)) *Cond {
+	var group Cond
+	fn(&group)
+	return cond.condHandle(union, &group)
+}
+
+func (cond *Cond) condHandle(union string, group *Cond) *Cond {
+	cond.conds = append(cond.conds, newGroupCond(union, group))
+	return cond
+}
+
+// WhereBetween 设置BETWEEN条件，匹配start和end之间（含两端）的值，作用和AndWhereBetween()一样
+func (cond *Cond) WhereBetween(field string, start, end interface{}) *Cond {
+	return cond.whereBetween("AND", field, start, end)
+}
+
+// AndWhereBetween 设置AND BETWEEN条件
+func (cond *Cond) AndWhereBetween(field string, start, end interface{}) *Cond {
+	return cond.whereBetween("AND", field, start, end)
+}
+
+// OrWhereBetween 设置OR BETWEEN条件
+func (cond *Cond) OrWhereBetween(field string, start, end interface{}) *Cond {
+	return cond.whereBetween("OR", field, start, end)
+}
+
+func (cond *Cond) whereBetween(union, field string, start, end interface{}) *Cond {
+	return cond.appendWhere(newWhereCond(union, field, "BETWEEN", []interface{}{start, end}))
+}
+
+// WhereNotBetween 设置NOT BETWEEN条件，作用和AndWhereNotBetween()一样
+func (cond *Cond) WhereNotBetween(field string, start, end interface{}) *Cond {
+	return cond.whereNotBetween("AND", field, start, end)
+}
+
+// AndWhereNotBetween 设置AND NOT BETWEEN条件
+func (cond *Cond) AndWhereNotBetween(field string, start, end interface{}) *Cond {
+	return cond.whereNotBetween("AND", field, start, end)
+}
+
+// OrWhereNotBetween 设置OR NOT BETWEEN条件
+func (cond *Cond) OrWhereNotBetween(field string, start, end interface{}) *Cond {
+	return cond.whereNotBetween("OR", field, start, end)
+}
+
+func (cond *Cond) whereNotBetween(union, field string, start, end interface{}) *Cond {
+	return cond.appendWhere(newWhereCond(union, field, "NOT BETWEEN", []interface{}{start, end}))
+}
+
+// WhereLike 设置LIKE条件，pattern中的%和_作为通配符使用，不会转义，作用和AndWhereLike()一样
+func (cond *Cond) WhereLike(field, pattern string) *Cond {
+	return cond.whereLike("AND", field, pattern)
+}
+
+// AndWhereLike 设置AND LIKE条件
+func (cond *Cond) AndWhereLike(field, pattern string) *Cond {
+	return cond.whereLike("AND", field, pattern)
+}
+
+// OrWhereLike 设置OR LIKE条件
+func (cond *Cond) OrWhereLike(field, pattern string) *Cond {
+	return cond.whereLike("OR", field, pattern)
+}
+
+func (cond *Cond) whereLike(union, field, pattern string) *Cond {
+	return cond.appendWhere(newWhereCond(union, field, "LIKE", pattern))
+}
+
+// WhereStartsWith 设置LIKE条件，匹配以value开头的值，value中的%和_会被转义，作用和AndWhereStartsWith()一样
+func (cond *Cond) WhereStartsWith(field, value string) *Cond {
+	return cond.whereStartsWith("AND", field, value)
+}
+
+// AndWhereStartsWith 设置AND LIKE前缀匹配条件
+func (cond *Cond) AndWhereStartsWith(field, value string) *Cond {
+	return cond.whereStartsWith("AND", field, value)
+}
+
+// OrWhereStartsWith 设置OR LIKE前缀匹配条件
+func (cond *Cond) OrWhereStartsWith(field, value string) *Cond {
+	return cond.whereStartsWith("OR", field, value)
+}
+
+func (cond *Cond) whereStartsWith(union, field, value string) *Cond {
+	return cond.appendWhere(newWhereCond(union, field, "LIKE", likePattern(escapeLike(value)+"%")))
+}
+
+// WhereEndsWith 设置LIKE条件，匹配以value结尾的值，value中的%和_会被转义，作用和AndWhereEndsWith()一样
+func (cond *Cond) WhereEndsWith(field, value string) *Cond {
+	return cond.whereEndsWith("AND", field, value)
+}
+
+// AndWhereEndsWith 设置AND LIKE后缀匹配条件
+func (cond *Cond) AndWhereEndsWith(field, value string) *Cond {
+	return cond.whereEndsWith("AND", field, value)
+}
+
+// OrWhereEndsWith 设置OR LIKE后缀匹配条件
+func (cond *Cond) OrWhereEndsWith(field, value string) *Cond {
+	return cond.whereEndsWith("OR", field, value)
+}
+
+func (cond *Cond) whereEndsWith(union, field, value string) *Cond {
+	return cond.appendWhere(newWhereCond(union, field, "LIKE", likePattern("%"+escapeLike(value))))
+}
+
+// WhereContains 设置LIKE条件，匹配包含value的值，value中的%和_会被转义，作用和AndWhereContains()一样
+func (cond *Cond) WhereContains(field, value string) *Cond {
+	return cond.whereContains("AND", field, value)
+}
+
+// AndWhereContains 设置AND LIKE包含匹配条件
+func (cond *Cond) AndWhereContains(field, value string) *Cond {
+	return cond.whereContains("AND", field, value)
+}
+
+// OrWhereContains 设置OR LIKE包含匹配条件
+func (cond *Cond) OrWhereContains(field, value string) *Cond {
+	return cond.whereContains("OR", field, value)
+}
+
+func (cond *Cond) whereContains(union, field, value string) *Cond {
+	return cond.appendWhere(newWhereCond(union, field, "LIKE", likePattern("%"+escapeLike(value)+"%")))
+}
+
+// WhereNull 设置IS NULL条件，作用和AndWhereNull()一样
+func (cond *Cond) WhereNull(field string) *Cond {
+	return cond.whereNull("AND", field)
+}
+
+// AndWhereNull 设置AND IS NULL条件
+func (cond *Cond) AndWhereNull(field string) *Cond {
+	return cond.whereNull("AND", field)
+}
+
+// OrWhereNull 设置OR IS NULL条件
+func (cond *Cond) OrWhereNull(field string) *Cond {
+	return cond.whereNull("OR", field)
+}
+
+func (cond *Cond) whereNull(union, field string) *Cond {
+	return cond.appendWhere(newWhereCond(union, field, "IS NULL", nil))
+}
+
+// WhereNotNull 设置IS NOT NULL条件，作用和AndWhereNotNull()一样
+func (cond *Cond) WhereNotNull(field string) *Cond {
+	return cond.whereNotNull("AND", field)
+}
+
+// AndWhereNotNull 设置AND IS NOT NULL条件
+func (cond *Cond) AndWhereNotNull(field string) *Cond {
+	return cond.whereNotNull("AND", field)
+}
+
+// OrWhereNotNull 设置OR IS NOT NULL条件
+func (cond *Cond) OrWhereNotNull(field string) *Cond {
+	return cond.whereNotNull("OR", field)
+}
+
+func (cond *Cond) whereNotNull(union, field string) *Cond {
+	return cond.appendWhere(newWhereCond(union, field, "IS NOT NULL", nil))
+}
+
+// WhereColumn 设置两个字段比较的条件，例如WhereColumn("updated_at", ">", "created_at")，作用和AndWhereColumn()一样
+func (cond *Cond) WhereColumn(field, operator, column string) *Cond {
+	return cond.whereColumn("AND", field, operator, column)
+}
+
+// AndWhereColumn 设置AND 字段比较条件
+func (cond *Cond) AndWhereColumn(field, operator, column string) *Cond {
+	return cond.whereColumn("AND", field, operator, column)
+}
+
+// OrWhereColumn 设置OR 字段比较条件
+func (cond *Cond) OrWhereColumn(field, operator, column string) *Cond {
+	return cond.whereColumn("OR", field, operator, column)
+}
+
+func (cond *Cond) whereColumn(union, field, operator, column string) *Cond {
+	return cond.appendWhere(newWhereCond(union, field, operator, columnName(column)))
+}
+
+// WhereRegexp 设置REGEXP正则匹配条件，作用和AndWhereRegexp()一样
+func (cond *Cond) WhereRegexp(field, pattern string) *Cond {
+	return cond.whereRegexp("AND", field, pattern)
+}
+
+// AndWhereRegexp 设置AND REGEXP条件
+func (cond *Cond) AndWhereRegexp(field, pattern string) *Cond {
+	return cond.whereRegexp("AND", field, pattern)
+}
+
+// OrWhereRegexp 设置OR REGEXP条件
+func (cond *Cond) OrWhereRegexp(field, pattern string) *Cond {
+	return cond.whereRegexp("OR", field, pattern)
+}
+
+func (cond *Cond) whereRegexp(union, field, pattern string) *Cond {
+	return cond.appendWhere(newWhereCond(union, field, "REGEXP", pattern))
+}
+
+// WhereExists 设置EXISTS子查询条件，sub必须是SELECT会话，作用和AndWhereExists()一样
+func (cond *Cond) WhereExists(sub *Session) *Cond {
+	return cond.whereExists("AND", sub)
+}
+
+// AndWhereExists 设置AND EXISTS子查询条件
+func (cond *Cond) AndWhereExists(sub *Session) *Cond {
+	return cond.whereExists("AND", sub)
+}
+
+// OrWhereExists 设置OR EXISTS子查询条件
+func (cond *Cond) OrWhereExists(sub *Session) *Cond {
+	return cond.whereExists("OR", sub)
+}
+
+func (cond *Cond) whereExists(union string, sub *Session) *Cond {
+	return cond.appendWhere(newWhereCond(union, "", "EXISTS", sub))
+}
+
+// WhereNotExists 设置NOT EXISTS子查询条件，sub必须是SELECT会话，作用和AndWhereNotExists()一样
+func (cond *Cond) WhereNotExists(sub *Session) *Cond {
+	return cond.whereNotExists("AND", sub)
+}
+
+// AndWhereNotExists 设置AND NOT EXISTS子查询条件
+func (cond *Cond) AndWhereNotExists(sub *Session) *Cond {
+	return cond.whereNotExists("AND", sub)
+}
+
+// OrWhereNotExists 设置OR NOT EXISTS子查询条件
+func (cond *Cond) OrWhereNotExists(sub *Session) *Cond {
+	return cond.whereNotExists("OR", sub)
+}
+
+func (cond *Cond) whereNotExists(union string, sub *Session) *Cond {
+	return cond.appendWhere(newWhereCond(union, "", "NOT EXISTS", sub))
+}
+
+//追加一个条件
+func (cond *Cond) appendWhere(c *whereCond) *Cond {
+	cond.conds = append(cond.conds, c)
+	return cond
+}
+
+//创建一个条件
+func newWhereCond(union, field, operator string, value interface{}) *whereCond {
+	var cond whereCond
+	cond.union = union
+	cond.field = field
+	//运算符统一转为大写，以便检查是否在允许的范围内
+	cond.operator = strings.ToUpper(strings.TrimSpace(operator))
+	cond.value = value
+	return &cond
+}
+
+//创建一个条件组，组内只有一个条件时不再额外用括号包裹
+//group为nil时创建没有条件的条件组，构建时会被跳过
+func newGroupCond(union string, group *Cond) *whereCond {
+	if group != nil && len(group.conds) == 1 {
+		cond := *group.conds[0]
+		cond.union = union
+		return &cond
+	}
+	var cond whereCond
+	cond.union = union
+	cond.group = true
+	if group != nil {
+		cond.children = group.conds
+	}
+	return &cond
+}
@@ -0,0 +1,120 @@
+package mysqlib
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//时间字面量的格式
+const timeFormat = "2006-01-02 15:04:05.999999"
+
+//将参数值编码成SQL字面量，用于Build(true)时直接拼接到语句中
+//字符串、时间使用单引号包裹，[]byte使用十六进制字面量，nil和nil指针为NULL
+//实现了driver.Valuer接口的值先取得其Value()的结果再编码
+func encodeValue(v interface{}, opt *Options) (string, error) {
+	if v == nil {
+		return "NULL", nil
+	}
+	rValue := reflect.ValueOf(v)
+	if rValue.Kind() == reflect.Ptr {
+		if rValue.IsNil() == true {
+			return "NULL", nil
+		}
+	}
+	if valuer, ok := v.(driver.Valuer); ok == true {
+		value, err := valuer.Value()
+		if err != nil {
+			return "", err
+		}
+		//Value()的结果不会再是driver.Valuer，避免无限递归
+		if _, ok := value.(driver.Valuer); ok == true {
+			return "", errors.New("`" + rValue.Type().String() + "`的Value()方法返回的值无法编码")
+		}
+		return encodeValue(value, opt)
+	}
+
+	switch value := v.(type) {
+	case []byte:
+		return "X'" + hex.EncodeToString(value) + "'", nil
+	case time.Time:
+		//零值时间与驱动的处理方式一致，否则Build(true)和Build(false)构建的语句查询结果不同
+		if value.IsZero() == true {
+			return "'0000-00-00 00:00:00'", nil
+		}
+		loc := opt.Location
+		if loc == nil {
+			loc = time.UTC
+		}
+		return "'" + value.In(loc).Format(timeFormat) + "'", nil
+	}
+
+	//按底层类型编码，以便支持自定义的类型，例如type Status int
+	switch rValue.Kind() {
+	case reflect.Ptr:
+		return encodeValue(rValue.Elem().Interface(), opt)
+	case reflect.String:
+		return quoteString(rValue.String(), opt.NoBackslashEscapes), nil
+	case reflect.Bool:
+		if rValue.Bool() == true {
+			return "1", nil
+		}
+		return "0", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rValue.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rValue.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		f := rValue.Float()
+		if math.IsNaN(f) == true || math.IsInf(f, 0) == true {
+			return "", errors.New("MySQL不支持的浮点数`" + strconv.FormatFloat(f, 'g', -1, 64) + "`")
+		}
+		return strconv.FormatFloat(f, 'g', -1, rValue.Type().Bits()), nil
+	case reflect.Slice:
+		//[]byte的自定义类型，例如json.RawMessage
+		if rValue.Type().Elem().Kind() == reflect.Uint8 {
+			return "X'" + hex.EncodeToString(rValue.Bytes()) + "'", nil
+		}
+	}
+	return "", errors.New("无法将`" + rValue.Type().String() + "`类型的值编码成SQL字面量")
+}
+
+//用单引号包裹字符串并转义
+//noBackslashEscapes为true时数据库启用了NO_BACKSLASH_ESCAPES模式，反斜杠不是转义符，只能把'转义成''
+func quoteString(v string, noBackslashEscapes bool) string {
+	var buf strings.Builder
+	buf.Grow(len(v) + 2)
+	buf.WriteByte('\'')
+	if noBackslashEscapes == true {
+		buf.WriteString(strings.Replace(v, "'", "''", -1))
+		buf.WriteByte('\'')
+		return buf.String()
+	}
+	for i := 0; i < len(v); i++ {
+		switch c := v[i]; c {
+		case '\x00':
+			buf.WriteString(`\0`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\x1a':
+			buf.WriteString(`\Z`)
+		case '\'':
+			buf.WriteString(`\'`)
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	buf.WriteByte('\'')
+	return buf.String()
+}
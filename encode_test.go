@@ -0,0 +1,180 @@
+package mysqlib
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestEncodeValue 测试各种类型的参数值编码成SQL字面量
+func TestEncodeValue(t *testing.T) {
+	type status uint8
+	type name string
+	var nilPtr *int
+	num := 7
+	cases := []struct {
+		value  interface{}
+		expect string
+	}{
+		{nil, "NULL"},
+		{nilPtr, "NULL"},
+		{&num, "7"},
+		{int8(-8), "-8"},
+		{int64(math.MinInt64), "-9223372036854775808"},
+		{uint(8), "8"},
+		{uint64(math.MaxUint64), "18446744073709551615"},
+		{status(2), "2"},
+		{float32(1.5), "1.5"},
+		{float64(0.1), "0.1"},
+		{true, "1"},
+		{false, "0"},
+		{"it's", `'it\'s'`},
+		{name(`a\b`), `'a\\b'`},
+		{"50%_off", "'50%_off'"},
+		{[]byte("ab'"), "X'616227'"},
+		{time.Date(2026, 10, 19, 8, 30, 0, 0, time.FixedZone("CST", 8*3600)), "'2026-10-19 00:30:00'"},
+		{time.Time{}, "'0000-00-00 00:00:00'"},
+		{sql.NullString{String: "dxv", Valid: true}, "'dxv'"},
+		{sql.NullInt64{}, "NULL"},
+	}
+	opt := &Options{}
+	for _, c := range cases {
+		literal, err := encodeValue(c.value, opt)
+		if err != nil {
+			t.Error(err.Error())
+			continue
+		}
+		if literal != c.expect {
+			t.Errorf("%#v 编码结果为 %s，应为 %s", c.value, literal, c.expect)
+		}
+	}
+
+	// 无法编码的值
+	for _, v := range []interface{}{math.NaN(), math.Inf(1), struct{}{}, []int{1}} {
+		if _, err := encodeValue(v, opt); err == nil {
+			t.Errorf("%#v 应该无法编码", v)
+		}
+	}
+
+	// NO_BACKSLASH_ESCAPES模式
+	literal, _ := encodeValue(`it's \n`, &Options{NoBackslashEscapes: true})
+	if literal != `'it''s \n'` {
+		t.Error("NO_BACKSLASH_ESCAPES模式编码结果不正确：", literal)
+	}
+
+	// 零值时间作为条件的值时仍然是比较，而不是与NULL比较
+	sess, err := New().Select(&User{}).Where("created_at", "=", time.Time{}).Build(true)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if sess.GetStmt() != "SELECT `id`, `username`, `password` FROM `user` WHERE (`created_at`='0000-00-00 00:00:00')" {
+		t.Error("构建的SQL语句不正确：", sess.GetStmt())
+	}
+}
+
+// 按MySQL的规则解析单引号包裹的字符串字面量，返回字面量表示的字符串
+// 字面量必须完整地占据整个输入，否则说明字符串提前结束，后面的内容会被当作SQL语句执行
+func parseStringLiteral(literal string, noBackslashEscapes bool) (string, bool) {
+	if len(literal) < 2 || literal[0] != '\'' {
+		return "", false
+	}
+	var buf strings.Builder
+	for i := 1; i < len(literal); i++ {
+		c := literal[i]
+		switch {
+		case c == '\\' && noBackslashEscapes == false:
+			i++
+			if i >= len(literal) {
+				return "", false
+			}
+			switch literal[i] {
+			case '0':
+				buf.WriteByte(0)
+			case 'n':
+				buf.WriteByte('\n')
+			case 'r':
+				buf.WriteByte('\r')
+			case 'Z':
+				buf.WriteByte('\x1a')
+			case 'b':
+				buf.WriteByte('\b')
+			case 't':
+				buf.WriteByte('\t')
+			case '%', '_':
+				buf.WriteByte('\\')
+				buf.WriteByte(literal[i])
+			default:
+				buf.WriteByte(literal[i])
+			}
+		case c == '\'':
+			//两个连续的单引号表示一个单引号
+			if i+1 < len(literal) && literal[i+1] == '\'' {
+				buf.WriteByte('\'')
+				i++
+				continue
+			}
+			//字面量结束的位置必须是输入的末尾
+			return buf.String(), i == len(literal)-1
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return "", false
+}
+
+// FuzzEncodeString 测试任意字符串编码后都能完整地解析回原值，不会提前结束字面量
+func FuzzEncodeString(f *testing.F) {
+	for _, v := range []string{"", "'", "\\", `\'`, "''", "a'; DROP TABLE `user`; -- ", "\x00\n\r\x1a\"", "50%_off\\%"} {
+		f.Add(v)
+	}
+	f.Fuzz(func(t *testing.T, v string) {
+		for _, noBackslashEscapes := range []bool{false, true} {
+			literal, err := encodeValue(v, &Options{NoBackslashEscapes: noBackslashEscapes})
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			value, ok := parseStringLiteral(literal, noBackslashEscapes)
+			if ok == false || value != v {
+				t.Fatalf("%q 编码为 %s，无法解析回原值", v, literal)
+			}
+		}
+	})
+}
+
+// FuzzEncodeBytes 测试任意[]byte编码成十六进制字面量后都能解析回原值
+func FuzzEncodeBytes(f *testing.F) {
+	f.Add([]byte("X'00'"))
+	f.Fuzz(func(t *testing.T, v []byte) {
+		literal, err := encodeValue(v, &Options{})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if strings.HasPrefix(literal, "X'") == false || strings.HasSuffix(literal, "'") == false {
+			t.Fatalf("%q 编码为 %s，不是十六进制字面量", v, literal)
+		}
+		value, err := hex.DecodeString(literal[2 : len(literal)-1])
+		if err != nil || string(value) != string(v) {
+			t.Fatalf("%q 编码为 %s，无法解析回原值", v, literal)
+		}
+	})
+}
+
+// TestBuildEncodeError 测试无法编码的参数值只影响本次构建，同一个会话仍然可以构建占位符语句
+func TestBuildEncodeError(t *testing.T) {
+	sess := New().Select(&User{}).Where("id", "=", math.NaN())
+	if _, err := sess.Build(true); err == nil {
+		t.Error("NaN应该无法编码")
+		return
+	}
+	if _, err := sess.Build(false); err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if _, err := sess.Build(true); err == nil {
+		t.Error("NaN应该无法编码")
+	}
+}
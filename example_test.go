@@ -1,6 +1,7 @@
 package mysqlib
 
 import (
+	"reflect"
 	"testing"
 
 	"log"
@@ -16,18 +17,46 @@ var mysql *Instance
 // User 定义模型
 type User struct {
 	tableName struct{} `sql:"user"`
-	ID        int64    `sql:"id"`
+	ID        int64    `sql:"id,auto_increment"` //标记自增字段，批量INSERT后可以写回自增ID
 	Username  string   `sql:"username"`
 	Password  string   `sql:"password"`
 }
 
+// Profile 定义用户资料模型，用于演示JOIN查询
+type Profile struct {
+	tableName struct{} `sql:"profile"`
+	UserID    int64    `sql:"user_id"`
+	Avatar    string   `sql:"avatar"`
+}
+
+// UserProfile 定义嵌套了用户资料的模型，JOIN查询时用于接收两张表的字段
+// 嵌套结构体的标记值是其字段在结果集中的前缀，可以是JOIN的表名或别名
+type UserProfile struct {
+	tableName struct{} `sql:"user"`
+	ID        int64    `sql:"id"`
+	Username  string   `sql:"username"`
+	Profile   *Profile `sql:"profile"` //LEFT JOIN没有匹配到记录时为nil
+}
+
+// checkBuild 检查会话构建的SQL语句和参数
+func checkBuild(t *testing.T, sqlSess *Session, stmt string, values ...interface{}) {
+	t.Helper()
+	if sqlSess.GetStmt() != stmt {
+		t.Error("构建的SQL语句不正确：", sqlSess.GetStmt())
+	}
+	if len(sqlSess.GetValues()) != len(values) || (len(values) > 0 && reflect.DeepEqual(sqlSess.GetValues(), values) == false) {
+		t.Error("执行SQL语句所需要的参数不正确：", sqlSess.GetValues())
+	}
+}
+
 // TestInit 初始化
 func TestInit(t *testing.T) {
 	// 实例化一个构建器对象
 	mysql = New(&Options{
-		TableNameField:    "tableName", //标记表名的字段名
-		TagName:           "sql",       //标记字符
-		DisableModelCache: false,       //禁用模型缓存
+		TableNameField:    "tableName",      //标记表名的字段名
+		TagName:           "sql",            //标记字符
+		DisableModelCache: false,            //禁用模型缓存
+		CursorKey:         []byte("secret"), //游标分页的签名密钥
 	})
 }
 
@@ -95,6 +124,137 @@ func TestInsert(t *testing.T) {
 	//t.Log("受影响的行数：", count)
 }
 
+// TestInsertBatch 测试通过模型slice批量插入多条记录
+func TestInsertBatch(t *testing.T) {
+	users := []User{
+		{Username: "a", Password: "1"},
+		{Username: "b", Password: "2"},
+		{Username: "c", Password: "3"},
+	}
+	// 使用BatchSize()限制每条语句最多插入2条记录，超出时会分块成多条语句
+	sqlSess, err := mysql.Insert(&users).
+		Column("username", "password").
+		BatchSize(2).
+		Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	// 用会话实例的GetBatches方法获得分块后的所有语句及其参数
+	batches := sqlSess.GetBatches()
+	if len(batches) != 2 {
+		t.Error("应该分块成2条语句，实际为", len(batches))
+		return
+	}
+	for k, batch := range batches {
+		t.Log("构建的SQL语句：", batch.Stmt)
+		t.Log("执行SQL语句所需要的参数：", batch.Values)
+
+		//result, err := db.Exec(batch.Stmt, batch.Values...)
+		//if err != nil {
+		//	t.Error(err.Error())
+		//	return
+		//}
+		//id, err := result.LastInsertId()
+		//if err != nil {
+		//	t.Error(err.Error())
+		//	return
+		//}
+		id := int64(k*2 + 1)
+		// 将自增ID写回到模型slice中
+		if err = batch.SetInsertID(id); err != nil {
+			t.Error(err.Error())
+			return
+		}
+	}
+	t.Log("写回的自增ID：", users[0].ID, users[1].ID, users[2].ID)
+	if users[2].ID != 3 {
+		t.Error("自增ID写回错误")
+	}
+}
+
+// TestUpsert 测试构建INSERT ... ON DUPLICATE KEY UPDATE语句
+func TestUpsert(t *testing.T) {
+	users := []User{
+		{ID: 1, Username: "a", Password: "1"},
+		{ID: 2, Username: "b", Password: "2"},
+	}
+	// 遇到重复键时，password取要插入的值，username保持不变，并累加登录次数
+	sqlSess, err := mysql.Insert(&users).
+		OnDuplicateKeyUpdate("password").
+		OnDuplicateKeyIncr("login_count", 1).
+		OnDuplicateKeyExpr("updated_at", "NOW()").
+		Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	checkBuild(t, sqlSess,
+		"INSERT INTO `user` (`id`, `username`, `password`) VALUES (?, ?, ?), (?, ?, ?) "+
+			"ON DUPLICATE KEY UPDATE `password`=VALUES(`password`), `login_count`=`login_count`+?, `updated_at`=NOW();",
+		int64(1), "a", "1", int64(2), "b", "2", 1)
+
+	// 使用RowAlias()设置行别名，适用于MySQL 8.0.19及以上版本
+	sqlSess, err = mysql.Insert(&users[0]).
+		RowAlias("new").
+		OnDuplicateKeyUpdate("username", "password").
+		Build(true)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	checkBuild(t, sqlSess,
+		"INSERT INTO `user` (`id`, `username`, `password`) VALUES (1, 'a', '1') AS `new` "+
+			"ON DUPLICATE KEY UPDATE `username`=`new`.`username`, `password`=`new`.`password`;")
+}
+
+// TestInsertVariants 测试构建INSERT IGNORE、REPLACE INTO和INSERT ... SELECT语句
+func TestInsertVariants(t *testing.T) {
+	user := User{ID: 1, Username: "dxvgef", Password: "123456"}
+
+	// INSERT IGNORE
+	sqlSess, err := mysql.Insert(&user).Ignore().Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	checkBuild(t, sqlSess, "INSERT IGNORE INTO `user` (`id`, `username`, `password`) VALUES (?, ?, ?);",
+		int64(1), "dxvgef", "123456")
+
+	// REPLACE INTO
+	sqlSess, err = mysql.Replace(&user).Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	checkBuild(t, sqlSess, "REPLACE INTO `user` (`id`, `username`, `password`) VALUES (?, ?, ?);",
+		int64(1), "dxvgef", "123456")
+
+	// INSERT ... SELECT，将user表中的记录复制到user_backup表
+	selectSess := mysql.Select(&User{}).
+		Column("id", "username", "password").
+		Where("id", ">", 100)
+	sqlSess, err = mysql.Insert(&User{}).
+		Table("user_backup").
+		Column("id", "username", "password").
+		FromSelect(selectSess).
+		Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	// Table()指定的表名优先于模型中标记的表名，才能插入到user_backup表
+	checkBuild(t, sqlSess,
+		"INSERT INTO `user_backup` (`id`, `username`, `password`) SELECT `id`, `username`, `password` FROM `user` WHERE (`id`>?);",
+		100)
+
+	// SELECT会话不能为nil
+	if _, err = mysql.Insert(&User{}).FromSelect(nil).Build(false); err == nil {
+		t.Error("没有检查出FromSelect()的参数为nil")
+	}
+}
+
 // 测试构建UPDATE语句
 func TestUpdate(t *testing.T) {
 	var user User
@@ -132,6 +292,92 @@ func TestUpdate(t *testing.T) {
 	//t.Log("受影响的行数：", count)
 }
 
+// TestUpdateTrack 测试跟踪模型后只更新被修改的字段
+func TestUpdateTrack(t *testing.T) {
+	// 假设这是从数据库中查询出的记录
+	user := User{ID: 1, Username: "dxvgef", Password: "123456"}
+	if err := mysql.Track(&user); err != nil {
+		t.Error(err.Error())
+		return
+	}
+	user.Password = "654321"
+
+	// 构建失败时快照仍然保留
+	if _, err := mysql.Update(&user).Where("id", "= 1 OR 1 =", user.ID).Build(false); err == nil {
+		t.Error("没有检查出不合法的运算符")
+		return
+	}
+
+	// 不需要用Column()指定字段，同一个会话可以重复构建
+	sqlSess := mysql.Update(&user).Where("id", "=", user.ID)
+	if _, err := sqlSess.Build(false); err != nil {
+		t.Error(err.Error())
+		return
+	}
+	checkBuild(t, sqlSess, "UPDATE `user` SET `password`=? WHERE (`id`=?)", "654321", int64(1))
+	if _, err := sqlSess.Build(true); err != nil {
+		t.Error(err.Error())
+		return
+	}
+	checkBuild(t, sqlSess, "UPDATE `user` SET `password`='654321' WHERE (`id`=1)")
+
+	//_, err = db.Exec(sqlSess.GetStmt(), sqlSess.GetValues()...)
+	//if err != nil {
+	//	t.Error(err.Error())
+	//	return
+	//}
+
+	// 构建成功后快照被丢弃，再次更新时必须用Column()指定字段或者重新跟踪模型
+	if _, err := mysql.Update(&user).Where("id", "=", user.ID).Build(false); err == nil {
+		t.Error("没有指定字段的UPDATE会话应该返回错误")
+		return
+	}
+	if err := mysql.Track(&user); err != nil {
+		t.Error(err.Error())
+		return
+	}
+	// 没有被修改的字段
+	_, err := mysql.Update(&user).Where("id", "=", user.ID).Build(false)
+	if err != ErrNoChanges {
+		t.Error("没有返回ErrNoChanges")
+		return
+	}
+	t.Log(err.Error())
+	// 不再更新的模型需要丢弃快照
+	mysql.Untrack(&user)
+	if _, err = mysql.Update(&user).Where("id", "=", user.ID).Build(false); err == nil || err == ErrNoChanges {
+		t.Error("丢弃快照后没有指定字段的UPDATE会话应该返回错误")
+	}
+}
+
+// TestUpdateExpr 测试构建UPDATE语句时使用自增、表达式和字段复制
+func TestUpdateExpr(t *testing.T) {
+	for _, final := range []bool{false, true} {
+		sqlSess, err := mysql.Update(&User{Username: "dxvgef"}).
+			Column("username").
+			Incr("login_count", 1).
+			Decr("credit", 0.5).
+			SetExpr("updated_at", "NOW()").
+			SetColumn("nickname", "username").
+			Where("id", "=", 1).
+			Build(final)
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		if final == true {
+			checkBuild(t, sqlSess,
+				"UPDATE `user` SET `username`='dxvgef', `login_count`=`login_count`+1, `credit`=`credit`-0.5, "+
+					"`updated_at`=NOW(), `nickname`=`username` WHERE (`id`=1)")
+			continue
+		}
+		checkBuild(t, sqlSess,
+			"UPDATE `user` SET `username`=?, `login_count`=`login_count`+?, `credit`=`credit`-?, "+
+				"`updated_at`=NOW(), `nickname`=`username` WHERE (`id`=?)",
+			"dxvgef", 1, 0.5, 1)
+	}
+}
+
 // TestSelect 测试构建SELECT语句
 func TestSelect(t *testing.T) {
 	var user User
@@ -168,6 +414,38 @@ func TestSelect(t *testing.T) {
 	//t.Log(user.Username)
 }
 
+// TestSelectTable 测试Table()指定的表名优先于模型中标记的表名
+func TestSelectTable(t *testing.T) {
+	var user User
+	sqlSess, err := mysql.Select(&user).
+		Table("user_archive").
+		Where("id", "=", 1).
+		Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if sqlSess.GetStmt() != "SELECT `id`, `username`, `password` FROM `user_archive` WHERE (`id`=?)" {
+		t.Error("没有使用Table()指定的表名：", sqlSess.GetStmt())
+		return
+	}
+	t.Log("构建的SQL语句：", sqlSess.GetStmt())
+
+	// UPDATE和DELETE会话同样以Table()指定的表名为准
+	sqlSess, err = mysql.Update(&user).Table("user_archive").Column("username").Where("id", "=", 1).Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	checkBuild(t, sqlSess, "UPDATE `user_archive` SET `username`=? WHERE (`id`=?)", "", 1)
+	sqlSess, err = mysql.Delete(&user).Table("user_archive").Where("id", "=", 1).Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	checkBuild(t, sqlSess, "DELETE FROM `user_archive` WHERE (`id`=?)", 1)
+}
+
 // TestSelectMultiple 测试通过模型slice查询多条记录
 func TestSelectMultiple(t *testing.T) {
 	// 注意这里定义的是模型的slice
@@ -206,6 +484,868 @@ func TestSelectMultiple(t *testing.T) {
 	//}
 }
 
+// TestSelectJoin 测试构建JOIN查询语句
+func TestSelectJoin(t *testing.T) {
+	var users []User
+	// 使用As()设置主表的别名，Join()的表可以是表名或模型
+	sqlSess, err := mysql.Select(&users).
+		As("u").
+		LeftJoin(&Profile{}, "p", "u.id = p.user_id").
+		Where("p.avatar", "<>", "").
+		OrderBy("u.id", "DESC").
+		Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	checkBuild(t, sqlSess,
+		"SELECT `u`.`id`, `u`.`username`, `u`.`password` FROM `user` AS `u` LEFT JOIN `profile` AS `p` ON `u`.`id` = `p`.`user_id` "+
+			"WHERE (`p`.`avatar`<>?) ORDER BY `u`.`id` DESC",
+		"")
+
+	// 使用嵌套结构体的模型，没有用Column()指定字段时，会同时查询JOIN的表中对应嵌套结构体的字段
+	var userProfiles []UserProfile
+	sqlSess, err = mysql.Select(&userProfiles).
+		As("u").
+		LeftJoin(&Profile{}, "p", "u.id = p.user_id").
+		Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	checkBuild(t, sqlSess,
+		"SELECT `u`.`id`, `u`.`username`, `p`.`user_id`, `p`.`avatar` FROM `user` AS `u` LEFT JOIN `profile` AS `p` ON `u`.`id` = `p`.`user_id`")
+
+	//rows, err := db.Query(sqlSess.GetStmt(), sqlSess.GetValues()...)
+	//if err != nil {
+	//	t.Error(err.Error())
+	//	return
+	//}
+	//
+	//// p.user_id和p.avatar会赋值到Profile中
+	//err = sqlSess.ScanModelSlice(rows)
+	//if err != nil {
+	//	t.Error(err.Error())
+	//	return
+	//}
+}
+
+// Author 定义作者模型，与Book互相嵌套
+type Author struct {
+	tableName struct{} `sql:"author"`
+	ID        int64    `sql:"id"`
+	Name      string   `sql:"name"`
+	Book      *Book    `sql:"b"` //作者的代表作
+}
+
+// Book 定义图书模型，与Author互相嵌套
+type Book struct {
+	tableName struct{} `sql:"book"`
+	ID        int64    `sql:"id"`
+	Title     string   `sql:"title"`
+	AuthorID  int64    `sql:"author_id"`
+	Author    *Author  `sql:"a"`
+}
+
+// Department 定义部门模型，嵌套了自身作为上级部门
+type Department struct {
+	tableName struct{}    `sql:"department"`
+	ID        int64       `sql:"id"`
+	ParentID  int64       `sql:"parent_id"`
+	Parent    *Department `sql:"parent"`
+}
+
+// TestSelectJoinCycle 测试互相嵌套和嵌套自身的模型
+func TestSelectJoinCycle(t *testing.T) {
+	cases := []struct {
+		model  interface{}
+		join   interface{}
+		alias  string
+		on     string
+		expect string
+	}{
+		{&[]Book{}, &Author{}, "a", "book.author_id = a.id",
+			"SELECT `book`.`id`, `book`.`title`, `book`.`author_id`, `a`.`id`, `a`.`name` FROM `book` LEFT JOIN `author` AS `a` ON `book`.`author_id` = `a`.`id`"},
+		{&[]Author{}, &Book{}, "b", "author.id = b.author_id",
+			"SELECT `author`.`id`, `author`.`name`, `b`.`id`, `b`.`title`, `b`.`author_id` FROM `author` LEFT JOIN `book` AS `b` ON `author`.`id` = `b`.`author_id`"},
+		{&[]Department{}, &Department{}, "parent", "department.parent_id = parent.id",
+			"SELECT `department`.`id`, `department`.`parent_id`, `parent`.`id`, `parent`.`parent_id` FROM `department` LEFT JOIN `department` AS `parent` ON `department`.`parent_id` = `parent`.`id`"},
+	}
+	// 分别使用有缓存和没有缓存的构建器
+	for _, builder := range []*Instance{New(), New(&Options{DisableModelCache: true})} {
+		for _, c := range cases {
+			sqlSess, err := builder.Select(c.model).LeftJoin(c.join, c.alias, c.on).Build(false)
+			if err != nil {
+				t.Error(err.Error())
+				return
+			}
+			if sqlSess.GetStmt() != c.expect {
+				t.Error("构建的SQL语句不正确：", sqlSess.GetStmt())
+				return
+			}
+		}
+	}
+	t.Log("构建的SQL语句：", cases[0].expect)
+}
+
+// UserStat 定义接收分组统计结果的模型，字段标记与聚合字段的别名对应
+type UserStat struct {
+	Password string `sql:"password"`
+	Total    int64  `sql:"total"`
+	MaxID    int64  `sql:"max_id"`
+}
+
+// TestSelectGroupBy 测试构建GROUP BY和HAVING语句
+func TestSelectGroupBy(t *testing.T) {
+	var stats []UserStat
+	// 模型中没有定义表名，需要用Table()指定
+	sqlSess, err := mysql.Select(&stats).
+		Table("user").
+		Column("password").
+		ColumnCount("*", "total").
+		ColumnMax("id", "max_id").
+		Where("id", ">", 0).
+		GroupBy("password").
+		Having("total", ">", 1).
+		OrderBy("total", "DESC").
+		Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	checkBuild(t, sqlSess,
+		"SELECT `password`, COUNT(*) AS `total`, MAX(`id`) AS `max_id` FROM `user` WHERE (`id`>?) "+
+			"GROUP BY `password` HAVING (`total`>?) ORDER BY `total` DESC",
+		0, 1)
+
+	//rows, err := db.Query(sqlSess.GetStmt(), sqlSess.GetValues()...)
+	//if err != nil {
+	//	t.Error(err.Error())
+	//	return
+	//}
+	//// 聚合字段按别名赋值到模型中
+	//err = sqlSess.ScanModelSlice(rows)
+	//if err != nil {
+	//	t.Error(err.Error())
+	//	return
+	//}
+
+	// 只查询一个聚合值时，可以用ScanValues()赋值到变量
+	sqlSess, err = mysql.Select(&User{}).
+		ColumnSum("id", "sum_id").
+		Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	checkBuild(t, sqlSess, "SELECT SUM(`id`) AS `sum_id` FROM `user`")
+
+	//rows, err = db.Query(sqlSess.GetStmt(), sqlSess.GetValues()...)
+	//if err != nil {
+	//	t.Error(err.Error())
+	//	return
+	//}
+	//var sumID sql.NullInt64
+	//err = sqlSess.ScanValues(rows, &sumID)
+	//if err != nil {
+	//	t.Error(err.Error())
+	//	return
+	//}
+}
+
+// TestSelectCount 测试根据SELECT会话构建统计记录数和判断记录是否存在的语句
+func TestSelectCount(t *testing.T) {
+	var users []User
+	sqlSess := mysql.Select(&users).
+		Where("id", ">", 100).
+		OrderBy("id", "DESC").
+		Limit(10)
+
+	// 统计语句与分页查询使用相同的WHERE条件，并忽略ORDER BY/LIMIT/OFFSET
+	stmt, values, err := sqlSess.BuildCount(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	t.Log("构建的SQL语句：", stmt)
+	t.Log("执行SQL语句所需要的参数：", values)
+
+	stmt, values, err = sqlSess.BuildExists(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	t.Log("构建的SQL语句：", stmt)
+	t.Log("执行SQL语句所需要的参数：", values)
+
+	// 也可以直接执行查询
+	//count, err := sqlSess.Count(db)
+	//if err != nil {
+	//	t.Error(err.Error())
+	//	return
+	//}
+	//t.Log("记录数：", count)
+	//
+	//exists, err := sqlSess.Exists(db)
+	//if err != nil {
+	//	t.Error(err.Error())
+	//	return
+	//}
+	//t.Log("是否存在：", exists)
+	//
+	//// 只查询username字段，赋值到[]string
+	//var usernames []string
+	//err = sqlSess.Pluck(db, "username", &usernames)
+	//if err != nil {
+	//	t.Error(err.Error())
+	//	return
+	//}
+	//t.Log(usernames)
+}
+
+// TestSelectCondGroup 测试构建嵌套的WHERE条件组
+func TestSelectCondGroup(t *testing.T) {
+	var users []User
+	// 使用回调函数设置条件组，渲染为 ((a) OR (b)) AND (c)
+	sqlSess, err := mysql.Select(&users).
+		WhereGroup(func(c *Cond) {
+			c.Where("username", "=", "dxvgef").OrWhere("username", "=", "admin")
+		}).
+		Where("id", ">", 0).
+		Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	checkBuild(t, sqlSess,
+		"SELECT `id`, `username`, `password` FROM `user` WHERE ((`username`=?) OR (`username`=?)) AND (`id`>?)",
+		"dxvgef", "admin", 0)
+
+	// 使用Where()、And()、Or()、Not()组合条件树
+	sqlSess, err = mysql.Select(&users).
+		WhereCond(Or(
+			And(Where("id", ">", 10), Where("id", "<", 20)),
+			Not(Where("username", "=", "admin")),
+		)).
+		Build(true)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	checkBuild(t, sqlSess,
+		"SELECT `id`, `username`, `password` FROM `user` WHERE (((`id`>10) AND (`id`<20)) OR NOT ((`username`='admin')))")
+
+	// 原生条件语句整体用括号包裹，其中的OR不会改变前后条件的优先级
+	sqlSess, err = mysql.Select(&users).
+		WhereRaw("`id` = ? OR `id` = ?", 1, 2).
+		Where("username", "=", "admin").
+		OrWhereCond(Or(Where("id", "=", 3)).WhereRaw("`id` > ? OR `id` < ?", 10, 0)).
+		Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	checkBuild(t, sqlSess,
+		"SELECT `id`, `username`, `password` FROM `user` WHERE (`id` = ? OR `id` = ?) AND (`username`=?) OR ((`id`=?) AND (`id` > ? OR `id` < ?))",
+		1, 2, "admin", 3, 10, 0)
+
+	// 为nil的条件组会被跳过
+	sqlSess, err = mysql.Select(&users).
+		WhereCond(And(nil, Where("id", "=", 1), Not(nil))).
+		OrWhereCond(Or(nil)).
+		Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	checkBuild(t, sqlSess, "SELECT `id`, `username`, `password` FROM `user` WHERE (`id`=?)", 1)
+}
+
+// TestSelectPredicates 测试构建BETWEEN、LIKE、IS NULL、字段比较和REGEXP条件
+func TestSelectPredicates(t *testing.T) {
+	var users []User
+	for _, final := range []bool{false, true} {
+		sqlSess, err := mysql.Select(&users).
+			WhereBetween("id", 1, 100).
+			// value中的%和_会被转义，按字面匹配
+			WhereStartsWith("username", "50%_off").
+			OrWhereContains("username", "dxv").
+			WhereNotNull("password").
+			WhereColumn("username", "<>", "password").
+			OrWhereRegexp("username", "^[a-z]+$").
+			Build(final)
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		t.Log("构建的SQL语句：", sqlSess.GetStmt())
+		t.Log("执行SQL语句所需要的参数：", sqlSess.GetValues())
+	}
+}
+
+// TestSelectLikeEscape 测试转义了通配符的LIKE条件会声明转义符，NO_BACKSLASH_ESCAPES模式下同样按字面匹配
+func TestSelectLikeEscape(t *testing.T) {
+	cases := []struct {
+		options *Options
+		expect  string
+	}{
+		{&Options{}, "SELECT `id`, `username`, `password` FROM `user` WHERE (`username` LIKE '50\\\\%\\\\_off%' ESCAPE '\\\\')"},
+		{&Options{NoBackslashEscapes: true}, "SELECT `id`, `username`, `password` FROM `user` WHERE (`username` LIKE '50\\%\\_off%' ESCAPE '\\')"},
+	}
+	for _, c := range cases {
+		sqlSess, err := New(c.options).Select(&User{}).WhereStartsWith("username", "50%_off").Build(true)
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		if sqlSess.GetStmt() != c.expect {
+			t.Error("构建的SQL语句不正确：", sqlSess.GetStmt())
+			return
+		}
+		t.Log("构建的SQL语句：", sqlSess.GetStmt())
+	}
+}
+
+// TestSelectIn测试构建IN和NOT IN条件，slice中的每个元素对应一个?占位符
+func TestSelectIn(t *testing.T) {
+	var users []User
+	type status uint8
+	stmts := map[bool]string{
+		// 每个元素对应一个?占位符，参数值按顺序展开
+		false: "SELECT `id`, `username`, `password` FROM `user` WHERE (`id` IN (?, ?, ?)) AND (`username` NOT IN (?, ?)) " +
+			"OR (`id` IN (?, ?)) OR (`status` IN (?, ?)) OR (1=0)",
+		true: "SELECT `id`, `username`, `password` FROM `user` WHERE (`id` IN (1, 2, 3)) AND (`username` NOT IN ('admin', 'root')) " +
+			"OR (`id` IN (7, 8)) OR (`status` IN (1, 2)) OR (1=0)",
+	}
+	for _, final := range []bool{false, true} {
+		sqlSess, err := mysql.Select(&users).
+			WhereIn("id", []int64{1, 2, 3}).
+			WhereNotIn("username", []string{"admin", "root"}).
+			OrWhereIn("id", [2]int{7, 8}).
+			// 自定义类型的slice也可以使用
+			OrWhereIn("status", []status{1, 2}).
+			// 空列表时IN恒为假
+			OrWhereIn("id", []int{}).
+			Build(final)
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		if final == true {
+			checkBuild(t, sqlSess, stmts[final])
+			continue
+		}
+		checkBuild(t, sqlSess, stmts[final],
+			int64(1), int64(2), int64(3), "admin", "root", 7, 8, status(1), status(2))
+	}
+
+	// 空列表时NOT IN恒为真
+	sqlSess, err := mysql.Select(&users).WhereNotIn("id", []int{}).Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	checkBuild(t, sqlSess, "SELECT `id`, `username`, `password` FROM `user` WHERE (1=1)")
+}
+
+// TestRaw 测试带有?占位符和参数的原生语句片段
+func TestRaw(t *testing.T) {
+	var stats []UserStat
+	for _, final := range []bool{false, true} {
+		sqlSess, err := mysql.Select(&stats).
+			Table("user").
+			Column("password").
+			ColumnRaw("IF(`password` = ?, 1, 0)", "total", "").
+			WhereRaw("DATE(`created_at`) = ?", "2026-10-19").
+			// 引号中的?不是占位符
+			OrWhereRaw("`username` LIKE CONCAT(?, '?%')", "dxv").
+			OrderByRaw("FIELD(`id`, ?, ?, ?)", 3, 1, 2).
+			Build(final)
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		t.Log("构建的SQL语句：", sqlSess.GetStmt())
+		t.Log("执行SQL语句所需要的参数：", sqlSess.GetValues())
+	}
+
+	sqlSess, err := mysql.Update(&User{Username: "dxvgef"}).
+		Column("username").
+		SetRaw("password", "SHA2(?, ?)", "123456", 256).
+		Where("id", "=", 1).
+		Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	t.Log("构建的SQL语句：", sqlSess.GetStmt())
+	t.Log("执行SQL语句所需要的参数：", sqlSess.GetValues())
+
+	// 占位符数量与参数数量不一致
+	_, err = mysql.Select(&stats).Table("user").WhereRaw("`id` = ?").Build(false)
+	if err == nil {
+		t.Error("没有检查出参数数量不一致")
+		return
+	}
+	t.Log(err.Error())
+}
+
+// TestSelectUnion 测试构建UNION语句，合并多个表的记录后统一排序和分页
+func TestSelectUnion(t *testing.T) {
+	var users []User
+	for _, final := range []bool{false, true} {
+		sqlSess, err := mysql.Select(&users).
+			Where("id", "<", 10).
+			UnionAll(
+				mysql.Select(&users).Table("admin").Where("username", "=", "dxvgef"),
+				mysql.Select(&users).Table("guest").WhereIn("id", []int{7, 8}),
+			).
+			OrderBy("id", "DESC").
+			Limit(20).
+			Build(final)
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		// ORDER BY和LIMIT作用于合并后的结果，参数值按各会话的顺序合并
+		if final == true {
+			checkBuild(t, sqlSess,
+				"SELECT `id`, `username`, `password` FROM `user` WHERE (`id`<10) "+
+					"UNION ALL SELECT `id`, `username`, `password` FROM `admin` WHERE (`username`='dxvgef') "+
+					"UNION ALL SELECT `id`, `username`, `password` FROM `guest` WHERE (`id` IN (7, 8)) ORDER BY `id` DESC LIMIT 20")
+			continue
+		}
+		checkBuild(t, sqlSess,
+			"SELECT `id`, `username`, `password` FROM `user` WHERE (`id`<?) "+
+				"UNION ALL SELECT `id`, `username`, `password` FROM `admin` WHERE (`username`=?) "+
+				"UNION ALL SELECT `id`, `username`, `password` FROM `guest` WHERE (`id` IN (?, ?)) ORDER BY `id` DESC LIMIT 20",
+			10, "dxvgef", 7, 8)
+	}
+
+	// 互相合并的会话无法构建，会返回错误而不是无限递归
+	a := mysql.Select(&users)
+	b := mysql.Select(&users).Table("admin")
+	a.Union(b)
+	b.UnionAll(a)
+	if _, err := a.Build(false); err == nil {
+		t.Error("没有检查出会话之间的循环引用")
+		return
+	}
+	if _, _, err := a.BuildCount(false); err == nil {
+		t.Error("没有检查出会话之间的循环引用")
+		return
+	}
+	// 通过WITH和子查询间接引用
+	c := mysql.Select(&users)
+	d := mysql.Select(&users).WhereIn("id", c)
+	c.With("d", d)
+	_, err := c.Build(true)
+	if err == nil {
+		t.Error("没有检查出会话之间的循环引用")
+		return
+	}
+	t.Log(err.Error())
+}
+
+// TestSelectLock 测试构建锁定读语句，例如从任务表中领取未被其它消费者锁定的任务
+func TestSelectLock(t *testing.T) {
+	var users []User
+	sqlSess, err := mysql.Select(&users).
+		Where("id", ">", 0).
+		OrderBy("id", "ASC").
+		Limit(10).
+		ForUpdate().
+		SkipLocked().
+		Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	checkBuild(t, sqlSess,
+		"SELECT `id`, `username`, `password` FROM `user` WHERE (`id`>?) ORDER BY `id` ASC LIMIT 10 FOR UPDATE SKIP LOCKED",
+		0)
+
+	// 使用JOIN时只锁定主表的记录
+	var rows []UserProfile
+	sqlSess, err = mysql.Select(&rows).
+		As("u").
+		LeftJoin("profile", "p", "u.id = p.user_id").
+		ForShare("u").
+		NoWait().
+		Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	checkBuild(t, sqlSess,
+		"SELECT `u`.`id`, `u`.`username`, `p`.`user_id`, `p`.`avatar` FROM `user` AS `u` "+
+			"LEFT JOIN `profile` AS `p` ON `u`.`id` = `p`.`user_id` FOR SHARE OF `u` NOWAIT")
+}
+
+// TestSelectHint 测试构建索引提示和优化器提示
+func TestSelectHint(t *testing.T) {
+	var users []User
+	sqlSess, err := mysql.Select(&users).
+		ForceIndex("idx_username").
+		IgnoreIndex("PRIMARY").
+		MaxExecutionTime(1000).
+		SetVar("sort_buffer_size", "16M").
+		Where("username", "=", "dxvgef").
+		Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	checkBuild(t, sqlSess,
+		"SELECT /*+ MAX_EXECUTION_TIME(1000) SET_VAR(sort_buffer_size=16M) */ `id`, `username`, `password` FROM `user` "+
+			"FORCE INDEX (`idx_username`) IGNORE INDEX (`PRIMARY`) WHERE (`username`=?)",
+		"dxvgef")
+
+	sqlSess, err = mysql.Delete(&User{}).
+		UseIndex("idx_username").
+		Where("username", "=", "dxvgef").
+		Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	// 单表DELETE语法不支持索引提示，使用多表DELETE语法
+	checkBuild(t, sqlSess, "DELETE `user` FROM `user` USE INDEX (`idx_username`) WHERE (`username`=?)", "dxvgef")
+}
+
+// Category 树形结构的分类表
+type Category struct {
+	tableName struct{} `sql:"category"`
+	ID        int64    `sql:"id"`
+	ParentID  int64    `sql:"parent_id"`
+	Name      string   `sql:"name"`
+}
+
+// TestSelectWith 测试构建公用表表达式和树形结构的递归查询
+func TestSelectWith(t *testing.T) {
+	var users []User
+	// 普通的公用表表达式
+	sqlSess, err := mysql.Select(&users).
+		With("active", mysql.Select(&users).Where("id", ">", 100)).
+		Table("active").
+		Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	checkBuild(t, sqlSess,
+		"WITH `active` AS (SELECT `id`, `username`, `password` FROM `user` WHERE (`id`>?)) SELECT `id`, `username`, `password` FROM `active`",
+		100)
+
+	// 查询分类3下面最多两层的子分类
+	var categories []Category
+	sqlSess, err = mysql.Descendants(&categories, 3, 2).Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	checkBuild(t, sqlSess,
+		"WITH RECURSIVE `tree` AS (SELECT `id`, `parent_id`, `name`, 0 AS `depth` FROM `category` WHERE (`id`=?) "+
+			"UNION ALL SELECT `c`.`id`, `c`.`parent_id`, `c`.`name`, `t`.`depth`+1 AS `depth` FROM `category` AS `c` "+
+			"INNER JOIN `tree` AS `t` ON `c`.`parent_id` = `t`.`id` WHERE (`t`.`depth`<?)) "+
+			"SELECT `id`, `parent_id`, `name` FROM `tree` WHERE (`depth`>?) ORDER BY `depth` ASC",
+		3, 2, 0)
+
+	// 查询分类3的所有上级分类
+	sqlSess, err = mysql.Ancestors(&categories, 3, 0).Build(true)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	// 深度为0时不限制层数
+	checkBuild(t, sqlSess,
+		"WITH RECURSIVE `tree` AS (SELECT `id`, `parent_id`, `name`, 0 AS `depth` FROM `category` WHERE (`id`=3) "+
+			"UNION ALL SELECT `c`.`id`, `c`.`parent_id`, `c`.`name`, `t`.`depth`+1 AS `depth` FROM `category` AS `c` "+
+			"INNER JOIN `tree` AS `t` ON `c`.`id` = `t`.`parent_id`) "+
+			"SELECT `id`, `parent_id`, `name` FROM `tree` WHERE (`depth`>0) ORDER BY `depth` ASC")
+
+	//rows, err := db.Query(sqlSess.GetStmt())
+	//if err != nil {
+	//	t.Error(err.Error())
+	//	return
+	//}
+	//if err = sqlSess.ScanModelSlice(rows); err != nil {
+	//	t.Error(err.Error())
+	//	return
+	//}
+	//t.Log(categories)
+}
+
+// TestSelectSubquery 测试构建子查询条件和派生表
+func TestSelectSubquery(t *testing.T) {
+	var users []User
+	var profiles []Profile
+	for _, final := range []bool{false, true} {
+		sqlSess, err := mysql.Select(&users).
+			Where("id", ">", 1).
+			WhereIn("id", mysql.Select(&profiles).Column("user_id").WhereStartsWith("avatar", "https://")).
+			WhereExists(mysql.Select(&profiles).WhereRaw("`profile`.`user_id` = `user`.`id`").Where("avatar", "<>", "")).
+			Where("password", "=", mysql.Select(&users).Table("admin").Column("password").Where("id", "=", 9).Limit(1)).
+			Where("username", "<>", "guest").
+			Build(final)
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+		// 子查询的参数值按其在语句中的位置合并
+		if final == true {
+			checkBuild(t, sqlSess,
+				"SELECT `id`, `username`, `password` FROM `user` WHERE (`id`>1) "+
+					"AND (`id` IN (SELECT `user_id` FROM `profile` WHERE (`avatar` LIKE 'https://%' ESCAPE '\\\\'))) "+
+					"AND (EXISTS (SELECT `user_id`, `avatar` FROM `profile` WHERE (`profile`.`user_id` = `user`.`id`) AND (`avatar`<>''))) "+
+					"AND (`password`=(SELECT `password` FROM `admin` WHERE (`id`=9) LIMIT 1)) AND (`username`<>'guest')")
+			continue
+		}
+		checkBuild(t, sqlSess,
+			"SELECT `id`, `username`, `password` FROM `user` WHERE (`id`>?) "+
+				"AND (`id` IN (SELECT `user_id` FROM `profile` WHERE (`avatar` LIKE ? ESCAPE '\\\\'))) "+
+				"AND (EXISTS (SELECT `user_id`, `avatar` FROM `profile` WHERE (`profile`.`user_id` = `user`.`id`) AND (`avatar`<>?))) "+
+				"AND (`password`=(SELECT `password` FROM `admin` WHERE (`id`=?) LIMIT 1)) AND (`username`<>?)",
+			1, "https://%", "", 9, "guest")
+	}
+
+	// 从派生表中查询
+	sqlSess, err := mysql.Select(&users).
+		From(mysql.Select(&users).Where("id", "<", 100).OrderBy("id", "DESC").Limit(10), "recent").
+		Where("username", "<>", "").
+		Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	checkBuild(t, sqlSess,
+		"SELECT `id`, `username`, `password` FROM (SELECT `id`, `username`, `password` FROM `user` WHERE (`id`<?) ORDER BY `id` DESC LIMIT 10) AS `recent` "+
+			"WHERE (`username`<>?)",
+		100, "")
+}
+
+// TestSelectPaginate 测试分页查询
+func TestSelectPaginate(t *testing.T) {
+	var users []User
+	// 只设置Offset()时会补上LIMIT，MySQL不支持单独使用OFFSET
+	sqlSess, err := mysql.Select(&users).Offset(20).Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	checkBuild(t, sqlSess, "SELECT `id`, `username`, `password` FROM `user` LIMIT 18446744073709551615 OFFSET 20")
+
+	// 模型不是slice、运算符不合法等错误在查询数据库之前就会返回，所以这里不需要数据库连接
+	if _, err = mysql.Select(&User{}).Paginate(nil, 1, 10); err == nil {
+		t.Error("没有检查出模型不是slice")
+		return
+	}
+	if _, err = mysql.Select(&users).Where("id", "= 1 OR 1 =", 1).Paginate(nil, 1, 10); err == nil {
+		t.Error("没有检查出不合法的运算符")
+		return
+	}
+
+	//page, err := mysql.Select(&users).
+	//	Where("id", ">", 0).
+	//	OrderBy("id", "DESC").
+	//	Paginate(db, 2, 10)
+	//if err != nil {
+	//	t.Error(err.Error())
+	//	return
+	//}
+	//t.Log("总记录数：", page.Total, "总页数：", page.Pages, "是否有下一页：", page.HasNext)
+	//t.Log(users)
+}
+
+// TestSelectKeyset 测试游标分页
+func TestSelectKeyset(t *testing.T) {
+	var users []User
+	// 第一页不需要游标，排序字段的组合必须唯一
+	sqlSess, err := mysql.Select(&users).
+		Where("password", "<>", "").
+		OrderBy("username", "ASC").
+		OrderBy("id", "ASC").
+		Limit(10).
+		After("").
+		Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	t.Log("构建的SQL语句：", sqlSess.GetStmt())
+
+	//rows, err := db.Query(sqlSess.GetStmt(), sqlSess.GetValues()...)
+	//if err != nil {
+	//	t.Error(err.Error())
+	//	return
+	//}
+	//if err = sqlSess.ScanModelSlice(rows); err != nil {
+	//	t.Error(err.Error())
+	//	return
+	//}
+	//cursor, err := sqlSess.NextCursor()
+	//if err != nil {
+	//	t.Error(err.Error())
+	//	return
+	//}
+	//// 用游标查询下一页
+	//var next []User
+	//sqlSess, err = mysql.Select(&next).
+	//	Where("password", "<>", "").
+	//	OrderBy("username", "ASC").
+	//	OrderBy("id", "ASC").
+	//	Limit(10).
+	//	After(cursor).
+	//	Build(false)
+
+	// 被篡改的游标
+	_, err = mysql.Select(&users).OrderBy("id", "ASC").After("eyJjIjpbImlkIl0sInYiOlsxXX0.xxxx").Build(false)
+	if err == nil {
+		t.Error("没有检查出被篡改的游标")
+		return
+	}
+	t.Log(err.Error())
+}
+
+// TestSelectValidate 测试不合法的运算符和标识符，Build会返回错误而不是构建语句
+func TestSelectValidate(t *testing.T) {
+	var users []User
+	// 运算符不区分大小写
+	if _, err := mysql.Select(&users).Where("username", "like", "dxv%").Build(false); err != nil {
+		t.Error(err.Error())
+		return
+	}
+	// 不在允许范围内的运算符
+	_, err := mysql.Select(&users).Where("id", "= 1 OR 1 =", 1).Build(false)
+	if err == nil {
+		t.Error("没有检查出不合法的运算符")
+		return
+	}
+	t.Log(err.Error())
+	// 形似内部标记的运算符也不在允许范围内
+	for _, operator := range []string{"[!raw!]", "[!RAW!]", "[!group!]", "[!GROUP!]"} {
+		sessions := []*Session{
+			mysql.Select(&users).Where("username = 1 OR 1=1 -- ", operator, "x"),
+			mysql.Select(&users).Where("id", "=", 1).OrWhere("id", operator, 2),
+			mysql.Select(&users).WhereCond(Or(Where("id", "=", 1), Where("id", operator, 2))),
+			mysql.Select(&users).GroupBy("password").Having("password", operator, "x"),
+		}
+		for _, sess := range sessions {
+			if _, err = sess.Build(false); err == nil {
+				t.Error("没有检查出不合法的运算符：", operator, sess.GetStmt())
+				return
+			}
+		}
+	}
+	// BETWEEN条件的值必须是最小值和最大值
+	for _, value := range []interface{}{5, nil, []int{1}, []int{1, 2, 3}} {
+		if _, err = mysql.Select(&users).Where("id", "between", value).Build(false); err == nil {
+			t.Error("没有检查出不合法的BETWEEN条件的值：", value)
+			return
+		}
+	}
+	// 包含反引号的排序字段
+	_, err = mysql.Select(&users).OrderBy("id` DESC, (SELECT 1)#", "ASC").Build(false)
+	if err == nil {
+		t.Error("没有检查出不合法的字段名")
+		return
+	}
+	t.Log(err.Error())
+	// INSERT/UPDATE指定的字段在模型中不存在
+	user := User{ID: 1}
+	for _, sess := range []*Session{
+		mysql.Insert(&user).Column("unknown"),
+		mysql.Update(&user).Column("username", "unknown").Where("id", "=", 1),
+		mysql.Insert(&benchAccessorUser{}).Column("unknown"),
+	} {
+		if _, err = sess.Build(false); err == nil {
+			t.Error("没有检查出模型中不存在的字段")
+			return
+		}
+	}
+}
+
+// TestMap 测试没有模型的表，用map读写记录
+func TestMap(t *testing.T) {
+	sqlSess, err := mysql.Table("log").
+		InsertMap(map[string]interface{}{"level": 3, "message": "登录失败"}).
+		Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	// 字段按名称排序
+	checkBuild(t, sqlSess, "INSERT INTO `log` (`level`, `message`) VALUES (?, ?);", 3, "登录失败")
+
+	sqlSess, err = mysql.Table("log").
+		UpdateMap(map[string]interface{}{"level": 1}).
+		Where("id", "=", 1).
+		Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	checkBuild(t, sqlSess, "UPDATE `log` SET `level`=? WHERE (`id`=?)", 1, 1)
+
+	sqlSess, err = mysql.Table("log").
+		Select().
+		Where("level", ">", 2).
+		Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	checkBuild(t, sqlSess, "SELECT * FROM `log` WHERE (`level`>?)", 2)
+
+	//logs, err := mysql.Table("log").Select("id", "message").Where("level", ">", 2).SelectMaps(db)
+	//if err != nil {
+	//	t.Error(err.Error())
+	//	return
+	//}
+	//t.Log(logs)
+}
+
+// TestScan 测试按结果集的字段名赋值，字段的顺序和数量不需要与模型一致
+func TestScan(t *testing.T) {
+	var rows []UserProfile
+	// 嵌套结构体的字段可以用“标记__字段名”作为别名
+	sqlSess, err := mysql.Select(&rows).
+		As("u").
+		Column("u.username", "u.id").
+		ColumnRaw("IFNULL(`p`.`avatar`, ?)", "profile__avatar", "default.png").
+		LeftJoin(&Profile{}, "p", "u.id = p.user_id").
+		Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	checkBuild(t, sqlSess,
+		"SELECT `u`.`username`, `u`.`id`, IFNULL(`p`.`avatar`, ?) AS `profile__avatar` FROM `user` AS `u` LEFT JOIN `profile` AS `p` ON `u`.`id` = `p`.`user_id`",
+		"default.png")
+
+	//result, err := db.Query(sqlSess.GetStmt(), sqlSess.GetValues()...)
+	//if err != nil {
+	//	t.Error(err.Error())
+	//	return
+	//}
+	//if err = sqlSess.ScanModelSlice(result); err != nil {
+	//	t.Error(err.Error())
+	//	return
+	//}
+
+	// 手写的SQL语句也可以赋值到任意标记了字段的结构体
+	//var users []User
+	//result, err = db.Query("SELECT * FROM `user` WHERE `id` > ?", 10)
+	//if err != nil {
+	//	t.Error(err.Error())
+	//	return
+	//}
+	//if err = mysql.Scan(result, &users); err != nil {
+	//	t.Error(err.Error())
+	//	return
+	//}
+}
+
 // TestDelete 测试构建DELETE语句
 func TestDelete(t *testing.T) {
 	// 使用空实例做模型
@@ -0,0 +1,132 @@
+package mysqlib
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+)
+
+//用于测试的数据库驱动，按顺序返回预先设置的记录集，并记录执行过的语句，不需要连接数据库
+type fakeDriver struct{}
+
+//每个测试使用的连接，key是sql.Open()的dsn
+var fakeConns = struct {
+	sync.Mutex
+	m map[string]*fakeConn
+}{m: make(map[string]*fakeConn)}
+
+func init() {
+	sql.Register("mysqlib_fake", fakeDriver{})
+}
+
+//打开测试用的数据库，Query()依次返回results中的记录集
+func openFakeDB(t *testing.T, results ...*fakeRows) (*sql.DB, *fakeConn) {
+	conn := &fakeConn{results: results}
+	fakeConns.Lock()
+	fakeConns.m[t.Name()] = conn
+	fakeConns.Unlock()
+	db, err := sql.Open("mysqlib_fake", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+		fakeConns.Lock()
+		delete(fakeConns.m, t.Name())
+		fakeConns.Unlock()
+	})
+	return db, conn
+}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	fakeConns.Lock()
+	defer fakeConns.Unlock()
+	conn := fakeConns.m[name]
+	if conn == nil {
+		return nil, errors.New("没有设置测试用的连接")
+	}
+	return conn, nil
+}
+
+//测试用的连接
+type fakeConn struct {
+	mu      sync.Mutex
+	results []*fakeRows      //还没有返回的记录集
+	queries []string         //执行过的语句
+	args    [][]driver.Value //执行语句时的参数
+}
+
+func (conn *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: conn, query: query}, nil
+}
+
+func (conn *fakeConn) Close() error {
+	return nil
+}
+
+func (conn *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("测试用的连接不支持事务")
+}
+
+//记录执行的语句并取出下一个记录集
+func (conn *fakeConn) query(query string, args []driver.Value) *fakeRows {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	conn.queries = append(conn.queries, query)
+	conn.args = append(conn.args, args)
+	if len(conn.results) == 0 {
+		return &fakeRows{}
+	}
+	rows := conn.results[0]
+	conn.results = conn.results[1:]
+	return rows
+}
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (stmt *fakeStmt) Close() error {
+	return nil
+}
+
+func (stmt *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (stmt *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	stmt.conn.query(stmt.query, args)
+	return driver.RowsAffected(1), nil
+}
+
+func (stmt *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return stmt.conn.query(stmt.query, args), nil
+}
+
+//测试用的记录集
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+	index   int
+}
+
+func (rows *fakeRows) Columns() []string {
+	return rows.columns
+}
+
+func (rows *fakeRows) Close() error {
+	return nil
+}
+
+func (rows *fakeRows) Next(dest []driver.Value) error {
+	if rows.index >= len(rows.rows) {
+		return io.EOF
+	}
+	copy(dest, rows.rows[rows.index])
+	rows.index++
+	return nil
+}
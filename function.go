@@ -1,115 +1,113 @@
 package mysqlib
 
 import (
-	"strconv"
+	"reflect"
 	"strings"
 )
 
-//过滤SQL敏感字符
-func filterSQL(v string) string {
-	// 判断 \b
-	v = strings.Replace(v, "\b", "\\b", -1)
-	// 判断 \r
-	v = strings.Replace(v, "\r", "\\r", -1)
-	// 判断 \n
-	v = strings.Replace(v, "\n", "\\n", -1)
-	// 判断 \x1a
-	v = strings.Replace(v, "\x1a", "\\x1a", -1)
-	// 判断 \x00
-	v = strings.Replace(v, "\x00", "\\x00", -1)
-	// 判断 _
-	// v = strings.Replace(v, "_", "\\_", -1)
-	// 判断 %
-	v = strings.Replace(v, "%", "\\%", -1)
-	// 判断 "
-	// 判断 <>&*;
-	// 判断 \
-	v = strings.Replace(v, "\\", "\\\\", -1)
-	// 转意 ‘
-	v = strings.Replace(v, "'", "\\'", -1)
-	return v
+//给字段名加上反引号，带有表名或别名的字段会分别给每一部分加上反引号，例如u.id => `u`.`id`
+func quoteField(field string) string {
+	parts := strings.Split(field, ".")
+	for k, v := range parts {
+		//u.*中的*不能加反引号
+		if v != "*" {
+			parts[k] = "`" + v + "`"
+		}
+	}
+	return strings.Join(parts, ".")
 }
 
-//将interface{}类型的参数断言并转换成string以用于拼接sql语句
-func interfaceToString(v interface{}) string {
-	var value string
-	switch v.(type) {
-	case string:
-		value = filterSQL(v.(string))
-	case int:
-		value = strconv.Itoa(v.(int))
-	case int8:
-		value = strconv.Itoa(int(v.(int8)))
-	case int16:
-		value = strconv.Itoa(int(v.(int16)))
-	case int32:
-		value = strconv.Itoa(int(v.(int32)))
-	case int64:
-		value = strconv.FormatInt(v.(int64), 10)
-	case float32:
-		value = strconv.FormatFloat(v.(float64), 'f', -1, 32)
-	case float64:
-		value = strconv.FormatFloat(v.(float64), 'f', -1, 64)
-	case bool:
-		value = strconv.FormatBool(v.(bool))
-	default:
-		value = "[!empty!]"
+//拆分结果集字段名中的前缀和字段名，前缀与字段名之间可以用.或者__分隔，例如p.avatar和p__avatar都会拆分为p和avatar
+func splitColumn(column string) (string, string) {
+	if i := strings.LastIndex(column, "."); i >= 0 {
+		return column[:i], column[i+1:]
+	}
+	if i := strings.Index(column, "__"); i > 0 {
+		return column[:i], column[i+2:]
 	}
+	return "", column
+}
 
-	return value
+//转义LIKE匹配模式中的通配符%和_，以及转义符\本身，使其按字面匹配
+func escapeLike(v string) string {
+	v = strings.Replace(v, "\\", "\\\\", -1)
+	v = strings.Replace(v, "%", "\\%", -1)
+	v = strings.Replace(v, "_", "\\_", -1)
+	return v
 }
 
-//将赋值给interface{}类型的slice参数断言并转换成string以用于拼接sql语句
-func sliceToString(v interface{}) []string {
-	var values []string
+//按?占位符切分原生语句，引号和注释中的?不是占位符
+//注释包括#和"-- "开头的单行注释，以及/* */多行注释，/*!开头的注释会被MySQL执行，其中的?仍然是占位符
+//返回的片段数量比占位符数量多一个
+func splitRaw(stmt string) []string {
+	parts, _ := scanRaw(stmt)
+	return parts
+}
 
-	switch v.(type) {
-	case []string:
-		value := v.([]string)
-		for _, v := range value {
-			values = append(values, filterSQL(v))
-		}
-	case []int:
-		value := v.([]int)
-		for _, v := range value {
-			values = append(values, strconv.Itoa(v))
-		}
-	case []int8:
-		value := v.([]int8)
-		for _, v := range value {
-			values = append(values, strconv.Itoa(int(v)))
-		}
-	case []int16:
-		value := v.([]int16)
-		for _, v := range value {
-			values = append(values, strconv.Itoa(int(v)))
-		}
-	case []int32:
-		value := v.([]int32)
-		for _, v := range value {
-			values = append(values, strconv.Itoa(int(v)))
-		}
-	case []int64:
-		value := v.([]int64)
-		for _, v := range value {
-			values = append(values, strconv.FormatInt(v, 10))
-		}
-	case []float32:
-		value := v.([]float32)
-		for _, v := range value {
-			values = append(values, strconv.FormatFloat(float64(v), 'f', -1, 32))
-		}
-	case []float64:
-		value := v.([]float64)
-		for _, v := range value {
-			values = append(values, strconv.FormatFloat(v, 'f', -1, 64))
-		}
-	case []bool:
-		value := v.([]bool)
-		for _, v := range value {
-			values = append(values, strconv.FormatBool(v))
+//与splitRaw()相同，另外返回语句结尾时所在的引号或注释：引号字符、\n表示单行注释、*表示多行注释，0表示都不在
+func scanRaw(stmt string) ([]string, byte) {
+	var parts []string
+	var quote byte   //当前所在的引号
+	var comment byte //当前所在的注释，\n表示单行注释，*表示多行注释
+	start := 0
+	for i := 0; i < len(stmt); i++ {
+		c := stmt[i]
+		switch {
+		//单行注释到换行符结束
+		case comment == '\n':
+			if c == '\n' {
+				comment = 0
+			}
+		//多行注释到*/结束
+		case comment == '*':
+			if c == '*' && i+1 < len(stmt) && stmt[i+1] == '/' {
+				comment = 0
+				i++
+			}
+		//引号中的反斜杠转义下一个字符
+		case quote != 0 && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '#':
+			comment = '\n'
+		//MySQL的--注释后面必须是空白字符或者语句结尾
+		case c == '-' && strings.HasPrefix(stmt[i:], "--") && (i+2 == len(stmt) || stmt[i+2] <= ' '):
+			comment = '\n'
+		case c == '/' && strings.HasPrefix(stmt[i:], "/*") && strings.HasPrefix(stmt[i:], "/*!") == false:
+			comment = '*'
+			i++
+		case c == '?':
+			parts = append(parts, stmt[start:i])
+			start = i + 1
 		}
 	}
+	if quote != 0 {
+		return append(parts, stmt[start:]), quote
+	}
+	return append(parts, stmt[start:]), comment
+}
 
+//将IN条件的值展开成参数列表，支持任意类型的slice和数组
+//[]byte以及不是slice的值作为单个参数，nil作为空列表
+func inValues(v interface{}) []interface{} {
+	if v == nil {
+		return nil
+	}
+	if _, ok := v.([]byte); ok == true {
+		return []interface{}{v}
+	}
+	rValue := reflect.ValueOf(v)
+	if rValue.Kind() != reflect.Slice && rValue.Kind() != reflect.Array {
+		return []interface{}{v}
+	}
+	values := make([]interface{}, rValue.Len())
+	for i := 0; i < rValue.Len(); i++ {
+		values[i] = rValue.Index(i).Interface()
+	}
 	return values
 }
@@ -0,0 +1,67 @@
+package mysqlib
+
+import (
+	"testing"
+)
+
+// TestSplitRaw 测试按?占位符切分原生语句，引号和注释中的?不是占位符
+func TestSplitRaw(t *testing.T) {
+	cases := []struct {
+		stmt   string
+		expect int //占位符数量
+	}{
+		{"`id` = ?", 1},
+		{"`a` = ? AND `b` = ?", 2},
+		{"`name` = '?' AND `id` = ?", 1},
+		{`"it\"s?" = ?`, 1},
+		{"`id` = ? -- 是否?\n AND `age` > ?", 2},
+		{"`id` = ? -- ?", 1},
+		{"`id` = ? --?", 2},
+		{"`id` = ? # ?\n", 1},
+		{"`id` = ? /* ? */ AND `age` > ?", 2},
+		{"`id` = ? /*! AND `age` > ? */", 2},
+		{"`id` = ? /* 没有结束的注释 ?", 1},
+		{"`a` - -? = 1", 1},
+	}
+	for _, c := range cases {
+		if count := len(splitRaw(c.stmt)) - 1; count != c.expect {
+			t.Errorf("%q 中有%d个占位符，应为%d个", c.stmt, count, c.expect)
+		}
+	}
+}
+
+// TestScanRaw 测试原生语句结尾时所在的引号或注释
+func TestScanRaw(t *testing.T) {
+	cases := []struct {
+		stmt   string
+		expect byte
+	}{
+		{"`id` = ?", 0},
+		{"`id` = ? -- 注释", '\n'},
+		{"`id` = ? # 注释\n", 0},
+		{"`id` = ? /* 注释", '*'},
+		{"`name` = 'abc", '\''},
+	}
+	for _, c := range cases {
+		if _, open := scanRaw(c.stmt); open != c.expect {
+			t.Errorf("%q 结尾时所在的引号或注释为%q，应为%q", c.stmt, open, c.expect)
+		}
+	}
+
+	// 以单行注释结尾的原生条件语句会换行，后面的括号和条件不会成为注释
+	sqlSess, err := New().Select(&User{}).
+		WhereRaw("`id` = ? -- 按ID查询", 1).
+		Where("username", "=", "admin").
+		Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if sqlSess.GetStmt() != "SELECT `id`, `username`, `password` FROM `user` WHERE (`id` = ? -- 按ID查询\n) AND (`username`=?)" {
+		t.Error("构建的SQL语句不正确：", sqlSess.GetStmt())
+	}
+	// 没有结束的引号或多行注释会吞掉后面的语句
+	if _, err = New().Select(&User{}).WhereRaw("`id` = 1 /* 注释").Build(false); err == nil {
+		t.Error("没有检查出没有结束的注释")
+	}
+}
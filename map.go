@@ -0,0 +1,133 @@
+package mysqlib
+
+import (
+	"database/sql"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Table 没有模型的表，用map读写记录，适用于表结构不固定的场景，例如后台管理工具
+type Table struct {
+	instance *Instance
+	name     string
+}
+
+// Table 创建没有模型的表，用InsertMap()、UpdateMap()、Select()、Delete()创建会话
+func (instance *Instance) Table(name string) *Table {
+	return &Table{instance: instance, name: name}
+}
+
+// InsertMap 创建INSERT会话，插入values中的字段及其值，字段按名称排序
+func (table *Table) InsertMap(values map[string]interface{}) *Session {
+	return table.mapHandle("INSERT", values)
+}
+
+// UpdateMap 创建UPDATE会话，更新values中的字段及其值，字段按名称排序
+func (table *Table) UpdateMap(values map[string]interface{}) *Session {
+	return table.mapHandle("UPDATE", values)
+}
+
+// Select 创建SELECT会话，columns是要查询的字段，为空时查询所有字段，用SelectMaps()执行查询
+func (table *Table) Select(columns ...string) *Session {
+	return table.instance.Select(nil).Table(table.name).Column(columns...)
+}
+
+// Delete 创建DELETE会话
+func (table *Table) Delete() *Session {
+	return table.instance.Delete(nil).Table(table.name)
+}
+
+func (table *Table) mapHandle(action string, values map[string]interface{}) *Session {
+	var sess *Session
+	if action == "INSERT" {
+		sess = table.instance.Insert(nil)
+	} else {
+		sess = table.instance.Update(nil)
+	}
+	sess.Table(table.name)
+	if len(values) == 0 {
+		sess.err = errors.New("没有要写入的字段")
+		return sess
+	}
+	//map的遍历顺序是随机的，按字段名排序使构建的语句保持一致
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		sess.AddValue(k, values[k])
+	}
+	return sess
+}
+
+// SelectMaps 执行SELECT会话的查询，将每条记录转换成map，key是结果集中的字段名
+// 字段值按字段类型转换：整数为int64（无符号为uint64），浮点数为float64，DECIMAL为string，
+// 字符串类型为string，二进制类型为[]byte，NULL为nil，时间类型在DSN中启用了parseTime时为time.Time，否则为string
+func (sess *Session) SelectMaps(db Querier) ([]map[string]interface{}, error) {
+	if sess.stmt.action != "SELECT" {
+		return nil, errors.New("只有SELECT会话才能执行此操作")
+	}
+	if _, err := sess.Build(false); err != nil {
+		return nil, err
+	}
+	rows, err := db.Query(sess.GetStmt(), sess.GetValues()...)
+	if err != nil {
+		return nil, err
+	}
+	return ScanMaps(rows)
+}
+
+// ScanMaps 将记录集的每条记录转换成map，字段值的转换规则与SelectMaps()相同
+func ScanMaps(rows *sql.Rows) ([]map[string]interface{}, error) {
+	defer rows.Close()
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	var result []map[string]interface{}
+	values := make([]interface{}, len(columnTypes))
+	pointers := make([]interface{}, len(columnTypes))
+	for k := range values {
+		pointers[k] = &values[k]
+	}
+	for rows.Next() {
+		if err = rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]interface{}, len(columnTypes))
+		for k, v := range columnTypes {
+			if row[v.Name()], err = convertValue(values[k], v.DatabaseTypeName()); err != nil {
+				return nil, err
+			}
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+//按字段类型转换驱动返回的值，dbType是字段类型名称，例如INT、UNSIGNED BIGINT、VARCHAR
+func convertValue(value interface{}, dbType string) (interface{}, error) {
+	b, ok := value.([]byte)
+	if ok == false {
+		//驱动已经转换成了Go的类型，例如int64、float64、time.Time
+		return value, nil
+	}
+	dbType = strings.ToUpper(dbType)
+	unsigned := strings.HasPrefix(dbType, "UNSIGNED ")
+	switch strings.TrimPrefix(dbType, "UNSIGNED ") {
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "INTEGER", "BIGINT", "YEAR":
+		if unsigned == true {
+			return strconv.ParseUint(string(b), 10, 64)
+		}
+		return strconv.ParseInt(string(b), 10, 64)
+	case "FLOAT", "DOUBLE", "REAL":
+		return strconv.ParseFloat(string(b), 64)
+	case "BINARY", "VARBINARY", "TINYBLOB", "BLOB", "MEDIUMBLOB", "LONGBLOB", "BIT", "GEOMETRY":
+		//database/sql扫描到*interface{}时已经复制了[]byte，可以直接返回
+		return b, nil
+	}
+	return string(b), nil
+}
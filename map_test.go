@@ -0,0 +1,55 @@
+package mysqlib
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestConvertValue 测试驱动返回的值按字段类型转换成Go的类型
+func TestConvertValue(t *testing.T) {
+	now := time.Date(2026, 10, 19, 8, 30, 0, 0, time.UTC)
+	cases := []struct {
+		value  interface{}
+		dbType string
+		expect interface{}
+	}{
+		{nil, "INT", nil},
+		{int64(-5), "BIGINT", int64(-5)},
+		{now, "DATETIME", now},
+		{[]byte("-5"), "INT", int64(-5)},
+		{[]byte("2026"), "YEAR", int64(2026)},
+		{[]byte("18446744073709551615"), "UNSIGNED BIGINT", uint64(18446744073709551615)},
+		{[]byte("255"), "unsigned tinyint", uint64(255)},
+		{[]byte("1.5"), "DOUBLE", float64(1.5)},
+		{[]byte("0.10"), "DECIMAL", "0.10"},
+		{[]byte("dxv"), "VARCHAR", "dxv"},
+		{[]byte("2026-10-19 08:30:00"), "DATETIME", "2026-10-19 08:30:00"},
+		{[]byte{0, 1}, "VARBINARY", []byte{0, 1}},
+		{[]byte{1}, "BIT", []byte{1}},
+	}
+	for _, c := range cases {
+		result, err := convertValue(c.value, c.dbType)
+		if err != nil {
+			t.Error(err.Error())
+			continue
+		}
+		if reflect.DeepEqual(result, c.expect) == false {
+			t.Errorf("%s类型的%#v 转换结果为 %#v，应为 %#v", c.dbType, c.value, result, c.expect)
+		}
+	}
+
+	// 无法转换的值
+	for _, c := range []struct {
+		value  []byte
+		dbType string
+	}{
+		{[]byte("-1"), "UNSIGNED INT"},
+		{[]byte("abc"), "INT"},
+		{[]byte("abc"), "FLOAT"},
+	} {
+		if _, err := convertValue(c.value, c.dbType); err == nil {
+			t.Errorf("%s类型的%q 应该无法转换", c.dbType, c.value)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package mysqlib
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// Page 分页查询的结果信息
+type Page struct {
+	Total   int64 //符合条件的记录总数
+	Page    int   //当前页码，从1开始
+	Size    int   //每页记录数
+	Pages   int   //总页数
+	HasNext bool  //是否还有下一页
+}
+
+// Paginate 分页查询，先统计符合条件的记录总数，再查询第page页的记录并赋值到模型slice
+// 统计和查询使用相同的WHERE/GROUP BY/HAVING条件，page小于1时按1处理，会覆盖会话中Limit()和Offset()的设置
+func (sess *Session) Paginate(db Querier, page, size int) (*Page, error) {
+	if size <= 0 {
+		return nil, errors.New("每页记录数必须大于0")
+	}
+	if page < 1 {
+		page = 1
+	}
+
+	//先构建查询语句，不需要查询数据库就能发现的错误不用等到统计之后再返回
+	sess.Limit(size).Offset((page - 1) * size)
+	if _, err := sess.Build(false); err != nil {
+		return nil, err
+	}
+	if sess.modelValue.isSlice == false {
+		return nil, errors.New("Paginate()的模型必须是slice的内存地址")
+	}
+
+	total, err := sess.Count(db)
+	if err != nil {
+		return nil, err
+	}
+	var result Page
+	result.Total = total
+	result.Page = page
+	result.Size = size
+	result.Pages = int((total + int64(size) - 1) / int64(size))
+	result.HasNext = page < result.Pages
+	//清空模型slice中原有的记录
+	sess.modelValue.rValue.Set(reflect.MakeSlice(sess.modelValue.rValue.Type(), 0, size))
+	//超出总页数时不需要再查询
+	if page > result.Pages {
+		return &result, nil
+	}
+
+	rows, err := db.Query(sess.GetStmt(), sess.GetValues()...)
+	if err != nil {
+		return nil, err
+	}
+	if err = sess.ScanModelSlice(rows); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// After 设置游标分页，查询游标所在记录之后的记录，cursor为空时查询第一页
+// 必须用OrderBy()设置排序字段，排序字段的组合必须唯一（通常以主键作为最后一个排序字段），且都是模型中的字段
+// 用Limit()设置每页记录数，ScanModelSlice()赋值后用NextCursor()得到下一页的游标
+func (sess *Session) After(cursor string) *Session {
+	return sess.keysetHandle(cursor, false)
+}
+
+// Before 设置游标分页，查询游标所在记录之前的记录，cursor为空时查询最后一页，用法与After()相同
+// ScanModelSlice()赋值后记录仍按OrderBy()的顺序排列，用PrevCursor()得到上一页的游标
+func (sess *Session) Before(cursor string) *Session {
+	return sess.keysetHandle(cursor, true)
+}
+
+func (sess *Session) keysetHandle(cursor string, before bool) *Session {
+	if sess.stmt.action != "SELECT" {
+		sess.err = errors.New("只有SELECT会话才能使用游标分页")
+		return sess
+	}
+	sess.stmt.keyset.cursor = cursor
+	sess.stmt.keyset.before = before
+	return sess
+}
+
+// NextCursor 得到模型slice中最后一条记录的游标，用于After()查询下一页，没有记录时返回空字符串
+func (sess *Session) NextCursor() (string, error) {
+	if sess.modelValue.isSlice == false || sess.modelValue.rValue.Len() == 0 {
+		return "", nil
+	}
+	return sess.encodeCursor(sess.modelValue.rValue.Index(sess.modelValue.rValue.Len() - 1))
+}
+
+// PrevCursor 得到模型slice中第一条记录的游标，用于Before()查询上一页，没有记录时返回空字符串
+func (sess *Session) PrevCursor() (string, error) {
+	if sess.modelValue.isSlice == false || sess.modelValue.rValue.Len() == 0 {
+		return "", nil
+	}
+	return sess.encodeCursor(sess.modelValue.rValue.Index(0))
+}
+
+//游标中签名的内容
+type cursorPayload struct {
+	Columns []string          `json:"c"` //排序字段，游标只能用于相同排序的查询
+	Values  []json.RawMessage `json:"v"` //排序字段的值
+}
+
+//游标分页的排序字段在模型中的字段名
+func (sess *Session) keysetColumns() ([]string, error) {
+	if len(sess.stmt.orders) == 0 {
+		return nil, errors.New("游标分页必须使用`OrderBy()`设置排序字段")
+	}
+	columns := make([]string, len(sess.stmt.orders))
+	for k, v := range sess.stmt.orders {
+		if v.raw != nil {
+			return nil, errors.New("游标分页不能使用`OrderByRaw()`")
+		}
+		_, columns[k] = splitColumn(v.field)
+		if _, ok := sess.modelInfo.fields[columns[k]]; ok == false {
+			return nil, errors.New("游标分页的排序字段`" + v.field + "`不是模型中的字段")
+		}
+	}
+	return columns, nil
+}
+
+//计算游标内容的签名
+func (sess *Session) signCursor(payload []byte) ([]byte, error) {
+	if len(sess.builder.options.CursorKey) == 0 {
+		return nil, errors.New("游标分页必须设置`Options.CursorKey`")
+	}
+	mac := hmac.New(sha256.New, sess.builder.options.CursorKey)
+	mac.Write(payload)
+	return mac.Sum(nil), nil
+}
+
+//把一条记录的排序字段的值编码成游标，格式为base64(内容).base64(签名)
+func (sess *Session) encodeCursor(row reflect.Value) (string, error) {
+	columns, err := sess.keysetColumns()
+	if err != nil {
+		return "", err
+	}
+	var payload cursorPayload
+	payload.Columns = columns
+	for _, v := range sess.rowValues(row, columns) {
+		value, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		payload.Values = append(payload.Values, value)
+	}
+	data, err := json.Marshal(&payload)
+	if err != nil {
+		return "", err
+	}
+	sign, err := sess.signCursor(data)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data) + "." + base64.RawURLEncoding.EncodeToString(sign), nil
+}
+
+//校验并解析游标，得到排序字段的值，值的类型与模型中字段的类型一致
+func (sess *Session) parseCursor() error {
+	sess.stmt.keyset.values = nil
+	if sess.stmt.keyset.cursor == "" {
+		return nil
+	}
+	columns, err := sess.keysetColumns()
+	if err != nil {
+		return err
+	}
+	invalid := errors.New("游标无效")
+	parts := strings.Split(sess.stmt.keyset.cursor, ".")
+	if len(parts) != 2 {
+		return invalid
+	}
+	data, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return invalid
+	}
+	sign, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return invalid
+	}
+	expected, err := sess.signCursor(data)
+	if err != nil {
+		return err
+	}
+	if hmac.Equal(sign, expected) == false {
+		return invalid
+	}
+	var payload cursorPayload
+	if err = json.Unmarshal(data, &payload); err != nil {
+		return invalid
+	}
+	//游标必须是相同排序的查询生成的
+	if len(payload.Columns) != len(columns) || len(payload.Values) != len(columns) {
+		return errors.New("游标与当前查询的排序字段不一致")
+	}
+	values := make([]interface{}, len(columns))
+	for k, column := range columns {
+		if payload.Columns[k] != column {
+			return errors.New("游标与当前查询的排序字段不一致")
+		}
+		value := reflect.New(sess.modelValue.rType.FieldByIndex(sess.modelInfo.fields[column].index).Type)
+		if err = json.Unmarshal(payload.Values[k], value.Interface()); err != nil {
+			return invalid
+		}
+		values[k] = value.Elem().Interface()
+	}
+	sess.stmt.keyset.values = values
+	return nil
+}
+
+//拼接带有游标条件的WHERE语句
+//排序字段为a ASC, b DESC时，After()的条件为 (a > ?) OR (a = ? AND b < ?)，原有的条件用括号包裹后与其AND连接
+func (sess *Session) buildKeysetWhere(final bool) string {
+	if sess.err != nil {
+		return ""
+	}
+	var stmt bytes.Buffer
+	stmt.WriteString(" WHERE ")
+	if len(sess.stmt.where.conds) > 0 {
+		stmt.WriteString("(")
+		stmt.WriteString(sess.buildConds(sess.stmt.where.conds, final))
+		stmt.WriteString(") AND ")
+	}
+	stmt.WriteString("(")
+	for k, order := range sess.stmt.orders {
+		if k > 0 {
+			stmt.WriteString(" OR ")
+		}
+		stmt.WriteString("(")
+		for i := 0; i < k; i++ {
+			stmt.WriteString(quoteField(sess.stmt.orders[i].field))
+			stmt.WriteString("=")
+			sess.writeValue(&stmt, sess.stmt.keyset.values[i], final)
+			stmt.WriteString(" AND ")
+		}
+		stmt.WriteString(quoteField(order.field))
+		//升序时之后的记录更大，降序时之后的记录更小，Before()则相反
+		if (order.direction == "ASC") != sess.stmt.keyset.before {
+			stmt.WriteString(">")
+		} else {
+			stmt.WriteString("<")
+		}
+		sess.writeValue(&stmt, sess.stmt.keyset.values[k], final)
+		stmt.WriteString(")")
+	}
+	stmt.WriteString(")")
+	return stmt.String()
+}
+
+//反转排序规则
+func reverseDirection(direction string) string {
+	if direction == "DESC" {
+		return "ASC"
+	}
+	return "DESC"
+}
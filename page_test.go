@@ -0,0 +1,57 @@
+package mysqlib
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+// TestPaginate 测试分页查询的总记录数、总页数及查询的语句
+func TestPaginate(t *testing.T) {
+	db, conn := openFakeDB(t,
+		&fakeRows{columns: []string{"count"}, rows: [][]driver.Value{{int64(25)}}},
+		&fakeRows{
+			columns: []string{"id", "username", "password"},
+			rows:    [][]driver.Value{{int64(11), []byte("a"), []byte("1")}, {int64(12), []byte("b"), []byte("2")}},
+		},
+		//超出总页数时只统计不查询
+		&fakeRows{columns: []string{"count"}, rows: [][]driver.Value{{int64(25)}}},
+	)
+	// 模型slice中原有的记录会被清空
+	users := []User{{ID: 99}}
+	sess := New().Select(&users).Where("id", ">", 0).OrderBy("id", "ASC")
+	page, err := sess.Paginate(db, 2, 10)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if *page != (Page{Total: 25, Page: 2, Size: 10, Pages: 3, HasNext: true}) {
+		t.Errorf("分页信息不正确：%+v", *page)
+	}
+	if len(users) != 2 || users[0].ID != 11 || users[1].Username != "b" {
+		t.Errorf("赋值的记录不正确：%+v", users)
+	}
+	queries := []string{
+		"SELECT COUNT(*) AS `count` FROM `user` WHERE (`id`>?)",
+		"SELECT `id`, `username`, `password` FROM `user` WHERE (`id`>?) ORDER BY `id` ASC LIMIT 10 OFFSET 10",
+	}
+	if reflect.DeepEqual(conn.queries, queries) == false {
+		t.Error("执行的语句不正确：", conn.queries)
+	}
+
+	// 最后一页之后没有记录，也没有下一页
+	page, err = sess.Paginate(db, 4, 10)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if *page != (Page{Total: 25, Page: 4, Size: 10, Pages: 3, HasNext: false}) {
+		t.Errorf("分页信息不正确：%+v", *page)
+	}
+	if len(users) != 0 {
+		t.Errorf("超出总页数时不应该有记录：%+v", users)
+	}
+	if len(conn.queries) != 3 {
+		t.Error("超出总页数时不应该查询记录：", conn.queries)
+	}
+}
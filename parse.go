@@ -1,11 +1,25 @@
 package mysqlib
 
 import (
+	"database/sql"
 	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
 )
 
 //解析模型结构
 func (sess *Session) parseModel() {
+	//没有模型的会话，例如InsertMap()、UpdateMap()
+	if sess.modelValue.Value == nil {
+		sess.modelInfo = &modelInfo{fields: make(map[string]*modelField)}
+		return
+	}
+
 	//将模型转为valueOf类型以便反射得到相关信息
 	model := reflect.ValueOf(sess.modelValue.Value)
 
@@ -15,77 +29,151 @@ func (sess *Session) parseModel() {
 	//得到模型的变量类型
 	modelKind := sess.modelValue.rValue.Kind()
 
-	//模型名称
-	var modelName string
-
 	//如果传进来的是结构体
 	if modelKind == reflect.Slice {
 		//保存模型的reflect.Type类型到session
 		sess.modelValue.rType = sess.modelValue.rValue.Type().Elem()
 		//标记是结构体
 		sess.modelValue.isSlice = true
-		//模型名
-		modelName = sess.modelValue.rValue.Type().Elem().String()
 	} else if modelKind == reflect.Struct {
 		//保存模型的reflect.Type类型到session
 		sess.modelValue.rType = model.Type().Elem()
-		//模型名
-		modelName = sess.modelValue.rValue.Type().String()
 	}
 
+	//获取模型信息
+	sess.modelInfo = sess.builder.getModelInfo(sess.modelValue.rType)
+
+	//会话的初始表名是从模型里的tag里读取的
+	//如果已经用Table()方法指定了表名，则以指定的为准
+	if sess.tableName == "" {
+		sess.tableName = sess.modelInfo.tableName
+	}
+}
+
+//获取模型信息，优先从缓存中读取
+func (instance *Instance) getModelInfo(rType reflect.Type) *modelInfo {
+	return instance.loadModelInfo(rType, nil)
+}
+
+//获取模型信息，reflecting是正在反射的模型，用于处理互相嵌套的结构体
+func (instance *Instance) loadModelInfo(rType reflect.Type, reflecting map[reflect.Type]*modelInfo) *modelInfo {
+	//模型名称
+	modelName := rType.String()
+
+	var info *modelInfo
 	//如果没有禁用模型缓存
-	if sess.builder.options.DisableModelCache == false {
+	if instance.options.DisableModelCache == false {
 		//从缓存中读取模型信息
-		sess.modelInfo = sess.builder.modelCache[modelName]
+		info = instance.modelCache[modelName]
 	}
 	//如果缓存中没有读到模型信息
-	if sess.modelInfo == nil {
+	if info == nil {
 		//再从反射中获取模型信息
-		sess.modelInfo = sess.reflectModel(modelName)
+		info = instance.reflectModel(rType, reflecting)
 		//把反射出来的模型信息写入到缓存
-		if sess.builder.options.DisableModelCache == false {
-			sess.builder.modelCache[sess.modelInfo.name] = sess.modelInfo
+		if instance.options.DisableModelCache == false {
+			instance.modelCache[info.name] = info
 		}
 	}
-
-	//会话的初始表名是从模型里的tag里读取的
-	//如果没有取到，后面可以再用Table()方法指定
-	sess.tableName = sess.modelInfo.tableName
+	return info
 }
 
 //反射结构体得到模型信息
-func (sess *Session) reflectModel(modelName string) *modelInfo {
+func (instance *Instance) reflectModel(rType reflect.Type, reflecting map[reflect.Type]*modelInfo) *modelInfo {
 	//创建一个模型信息
 	var info modelInfo
 	info.fields = make(map[string]*modelField)
-	info.name = modelName
+	info.name = rType.String()
+
+	//记录正在反射的模型，嵌套的结构体再引用它时不再重复反射
+	if reflecting == nil {
+		reflecting = make(map[reflect.Type]*modelInfo)
+	}
+	reflecting[rType] = &info
+	defer delete(reflecting, rType)
 
 	//取得结构体所有字段的总数
-	allFieldCount := sess.modelValue.rType.NumField()
+	allFieldCount := rType.NumField()
 
 	//如果字段总数>0才开始循环
 	if allFieldCount > 0 {
 		//遍历所有字段
 		for i := 0; i < allFieldCount; i++ {
 			var field modelField
-			field.VarName = sess.modelValue.rType.Field(i).Name
-			field.VarType = sess.modelValue.rType.Field(i).Type.Name()
-			field.SQLName = sess.modelValue.rType.Field(i).Tag.Get(sess.builder.options.TagName)
+			field.index = rType.Field(i).Index
+			field.VarName = rType.Field(i).Name
+			field.VarType = rType.Field(i).Type.Name()
+			field.SQLName = rType.Field(i).Tag.Get(instance.options.TagName)
 			//如果存在标记
 			if field.SQLName != "" {
 				//如果是标记表名的字段
-				if field.VarName == sess.builder.options.TableNameField {
+				if field.VarName == instance.options.TableNameField {
 					//赋值表名
 					info.tableName = field.SQLName
+				} else if nested := instance.reflectNested(rType.Field(i), reflecting); nested != nil {
+					//如果是嵌套的结构体，标记的值是其字段在结果集中的前缀
+					nested.prefix = field.SQLName
+					info.nested = append(info.nested, nested)
 				} else {
+					//拆分出标记中的字段名和选项
+					var options []string
+					field.SQLName, options = parseTag(field.SQLName)
+					for _, option := range options {
+						switch option {
+						case "auto_increment":
+							field.autoIncr = true
+							info.autoIncr = field.SQLName
+						}
+					}
 					//累加模型信息中的字段总数
 					info.fieldCount++
 					//写入字段信息
 					info.fields[field.SQLName] = &field
+					info.columns = append(info.columns, field.SQLName)
 				}
 			}
 		}
 	}
 
+	//访问器的字段与模型不一致时不使用访问器，避免读写不到字段
+	info.accessor = validAccessor(rType, info.columns)
+
 	return &info
 }
+
+//反射嵌套的结构体字段，例如`Profile *Profile `sql:"p"``，如果不是嵌套的结构体则返回nil
+//time.Time等实现了sql.Scanner接口的结构体，以及没有标记字段的结构体，仍然视为普通字段
+//嵌套的结构体引用了正在反射的模型时（包括引用自身），使用尚未反射完成的模型信息
+func (instance *Instance) reflectNested(structField reflect.StructField, reflecting map[reflect.Type]*modelInfo) *nestedField {
+	var nested nestedField
+	nested.index = structField.Index
+	nested.rType = structField.Type
+	if nested.rType.Kind() == reflect.Ptr {
+		nested.isPtr = true
+		nested.rType = nested.rType.Elem()
+	}
+	if nested.rType.Kind() != reflect.Struct || nested.rType == timeType ||
+		reflect.PtrTo(nested.rType).Implements(scannerType) {
+		return nil
+	}
+	//结构体之间互相嵌套，使用尚未反射完成的模型信息，避免无限递归
+	//正在反射的模型还没有汇总完字段，不能用字段数量判断是否是嵌套的结构体
+	if reflecting[nested.rType] != nil {
+		nested.info = reflecting[nested.rType]
+		return &nested
+	}
+	nested.info = instance.loadModelInfo(nested.rType, reflecting)
+	if len(nested.info.columns) == 0 {
+		return nil
+	}
+	return &nested
+}
+
+//拆分标记的值，第一部分是字段名，其余用逗号分隔的是选项，例如`sql:"id,auto_increment"`
+func parseTag(tag string) (string, []string) {
+	parts := strings.Split(tag, ",")
+	for k := range parts {
+		parts[k] = strings.TrimSpace(parts[k])
+	}
+	return parts[0], parts[1:]
+}
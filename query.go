@@ -0,0 +1,144 @@
+package mysqlib
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+)
+
+// Querier 执行查询语句的接口，*sql.DB和*sql.Tx都实现了此接口
+type Querier interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// BuildCount 根据SELECT会话构建统计记录数的语句，返回语句及其参数值，不影响Build()构建的结果
+// 统计时会忽略ORDER BY/LIMIT/OFFSET，使用了GROUP BY或HAVING时统计的是分组数量
+func (sess *Session) BuildCount(final bool) (string, []interface{}, error) {
+	return sess.buildAside(final, func() string {
+		//有分组或UNION时，把原语句作为派生表统计分组数量或合并后的记录数
+		if len(sess.stmt.groups) > 0 || len(sess.stmt.having) > 0 || len(sess.stmt.unions) > 0 {
+			return "SELECT COUNT(*) FROM (" + sess.buildQuery(final, false) + ") AS `t`"
+		}
+		sess.stmt.field = []*keyInterface{{key: "count", value: "COUNT(*)"}}
+		return sess.buildQuery(final, false)
+	})
+}
+
+// BuildExists 根据SELECT会话构建判断记录是否存在的语句，返回语句及其参数值，不影响Build()构建的结果
+func (sess *Session) BuildExists(final bool) (string, []interface{}, error) {
+	return sess.buildAside(final, func() string {
+		return "SELECT EXISTS(" + sess.buildQuery(final, false) + ")"
+	})
+}
+
+// Count 执行统计记录数的查询，与Build()构建的语句使用相同的WHERE条件
+func (sess *Session) Count(db Querier) (int64, error) {
+	stmt, values, err := sess.BuildCount(false)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := db.Query(stmt, values...)
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	err = sess.ScanValues(rows, &count)
+	return count, err
+}
+
+// Exists 执行判断记录是否存在的查询，与Build()构建的语句使用相同的WHERE条件
+func (sess *Session) Exists(db Querier) (bool, error) {
+	stmt, values, err := sess.BuildExists(false)
+	if err != nil {
+		return false, err
+	}
+	rows, err := db.Query(stmt, values...)
+	if err != nil {
+		return false, err
+	}
+	var exists bool
+	err = sess.ScanValues(rows, &exists)
+	return exists, err
+}
+
+// Pluck 只查询一个字段，并将所有记录的值赋值到dest，dest必须是slice的内存地址，例如&[]string{}
+// 查询时使用会话的WHERE/ORDER BY/LIMIT/OFFSET，忽略Column()指定的字段
+func (sess *Session) Pluck(db Querier, field string, dest interface{}) error {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.Elem().Kind() != reflect.Slice {
+		return errors.New("Pluck()的dest参数必须是slice的内存地址")
+	}
+	//UNION的各会话字段必须一致，无法只替换本会话的字段
+	if len(sess.stmt.unions) > 0 {
+		return errors.New("使用UNION的会话不能执行Pluck()")
+	}
+	//buildAside()在build函数替换字段之前就已经检查过会话的字段，需要单独检查field
+	if err := checkField(field); err != nil {
+		return err
+	}
+	stmt, values, err := sess.buildAside(false, func() string {
+		sess.stmt.field = []*keyInterface{{key: field}}
+		return sess.buildQuery(false, true)
+	})
+	if err != nil {
+		return err
+	}
+	rows, err := db.Query(stmt, values...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	slice := destValue.Elem()
+	for rows.Next() {
+		value := reflect.New(slice.Type().Elem())
+		if err = rows.Scan(value.Interface()); err != nil {
+			return err
+		}
+		slice.Set(reflect.Append(slice, value.Elem()))
+	}
+	return rows.Err()
+}
+
+//在不影响会话已构建结果的情况下构建另一条SELECT语句，build函数返回构建的语句
+func (sess *Session) buildAside(final bool, build func() string) (string, []interface{}, error) {
+	if sess.err != nil {
+		return "", nil, sess.err
+	}
+	if sess.stmt.action != "SELECT" {
+		return "", nil, errors.New("只有SELECT会话才能执行此操作")
+	}
+	if sess.stmt.building == true {
+		return "", nil, errors.New("会话之间存在循环引用")
+	}
+	sess.stmt.building = true
+
+	//构建完成后还原会话中已构建的结果
+	field := sess.stmt.field
+	resultString := sess.stmt.resultString
+	resultValues := sess.stmt.resultValues
+	defer func() {
+		sess.stmt.field = field
+		sess.stmt.resultString = resultString
+		sess.stmt.resultValues = resultValues
+		sess.stmt.building = false
+	}()
+
+	sess.stmt.resultValues = nil
+	sess.stmt.buildErr = nil
+	//解析模型结构
+	sess.parseModel()
+	//如果没有定义表名
+	if sess.tableName == "" {
+		return "", nil, errors.New("没有定义表名")
+	}
+	//检查标识符和运算符
+	if err := sess.validate(); err != nil {
+		return "", nil, err
+	}
+	stmt := build()
+	//构建过程中产生的错误，例如无法编码的参数值
+	if sess.stmt.buildErr != nil {
+		return "", nil, sess.stmt.buildErr
+	}
+	return stmt, sess.stmt.resultValues, nil
+}
@@ -0,0 +1,66 @@
+package mysqlib
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+// TestQuery 测试Count()、Exists()和Pluck()执行的语句及读取的结果
+func TestQuery(t *testing.T) {
+	db, conn := openFakeDB(t,
+		&fakeRows{columns: []string{"count"}, rows: [][]driver.Value{{int64(42)}}},
+		&fakeRows{columns: []string{"EXISTS"}, rows: [][]driver.Value{{int64(1)}}},
+		&fakeRows{columns: []string{"username"}, rows: [][]driver.Value{{[]byte("a")}, {[]byte("b")}}},
+	)
+	var users []User
+	sess := New().Select(&users).Where("id", ">", 5).OrderBy("id", "DESC").Limit(10)
+
+	count, err := sess.Count(db)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if count != 42 {
+		t.Error("记录数不正确：", count)
+	}
+	exists, err := sess.Exists(db)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if exists == false {
+		t.Error("记录应该存在")
+	}
+	var names []string
+	if err = sess.Pluck(db, "username", &names); err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if reflect.DeepEqual(names, []string{"a", "b"}) == false {
+		t.Error("Pluck()读取的值不正确：", names)
+	}
+
+	queries := []string{
+		"SELECT COUNT(*) AS `count` FROM `user` WHERE (`id`>?)",
+		"SELECT EXISTS(SELECT `id`, `username`, `password` FROM `user` WHERE (`id`>?))",
+		"SELECT `username` FROM `user` WHERE (`id`>?) ORDER BY `id` DESC LIMIT 10",
+	}
+	if reflect.DeepEqual(conn.queries, queries) == false {
+		t.Error("执行的语句不正确：", conn.queries)
+	}
+	for k, args := range conn.args {
+		if reflect.DeepEqual(args, []driver.Value{int64(5)}) == false {
+			t.Error("执行", conn.queries[k], "的参数不正确：", args)
+		}
+	}
+
+	// Pluck()的字段名不能注入SQL语句
+	err = sess.Pluck(db, "id` FROM user; DROP TABLE x; -- ", &names)
+	if err == nil {
+		t.Error("没有检查出不合法的字段名")
+	}
+	if len(conn.queries) != 3 {
+		t.Error("不合法的字段名不应该执行查询：", conn.queries[len(conn.queries)-1])
+	}
+}
@@ -2,22 +2,26 @@ package mysqlib
 
 import (
 	"database/sql"
+	"errors"
 	"reflect"
 )
 
 // ScanModelSlice 将到多条记录赋值到模型Slice
 func (sess *Session) ScanModelSlice(rows *sql.Rows) (err error) {
 	defer rows.Close()
+	//结果集的字段对应的模型字段
+	columns, err := sess.scanColumns(rows)
+	if err != nil {
+		return
+	}
+	//模型slice中原有的记录数
+	start := sess.modelValue.rValue.Len()
 	//遍历数据库返回的记录集
 	for rows.Next() {
-		//一行记录的载体
-		row := make([]interface{}, len(sess.stmt.field))
 		//根据模型的类型，动态创建一个结构体，用于存储一条记录
 		newRow := reflect.New(sess.modelValue.rType).Elem()
-		//遍历要输出的字段
-		for i, sqlName := range sess.stmt.field {
-			row[i] = newRow.FieldByName(sess.modelInfo.fields[sqlName.key].VarName).Addr().Interface()
-		}
+		//一行记录的载体，存放的是newRow各字段的内存地址
+		row, nested := sess.scanTargets(newRow, columns)
 		//获取记录集
 		err = rows.Scan(row...)
 		if err != nil {
@@ -26,23 +30,37 @@ func (sess *Session) ScanModelSlice(rows *sql.Rows) (err error) {
 			}
 			return
 		}
+		//给嵌套的结构体赋值
+		fillNested(newRow, nested)
 		//把newRow结构体append到模型中
 		sess.modelValue.rValue.Set(reflect.Append(sess.modelValue.rValue, newRow))
 	}
+	//游标分页查询之前的记录时是反向排序的，恢复成OrderBy()的顺序
+	if sess.stmt.keyset.before == true {
+		reverseSlice(sess.modelValue.rValue.Slice(start, sess.modelValue.rValue.Len()))
+	}
 
 	return
 }
 
+//反转slice中元素的顺序
+func reverseSlice(slice reflect.Value) {
+	swap := reflect.Swapper(slice.Interface())
+	for i, j := 0, slice.Len()-1; i < j; i, j = i+1, j-1 {
+		swap(i, j)
+	}
+}
+
 // ScanModel 将单条记录赋值到模型
 func (sess *Session) ScanModel(rows *sql.Rows) (err error) {
 	defer rows.Close()
-	//一行记录的载体
-	row := make([]interface{}, len(sess.stmt.field))
-	//遍历要输出的字段
-	for i, sqlName := range sess.stmt.field {
-		//将模型字段的内存地址赋值给oneRow记录的载体
-		row[i] = sess.modelValue.rValue.FieldByName(sess.modelInfo.fields[sqlName.key].VarName).Addr().Interface()
+	//结果集的字段对应的模型字段
+	columns, err := sess.scanColumns(rows)
+	if err != nil {
+		return
 	}
+	//一行记录的载体，存放的是模型各字段的内存地址
+	row, nested := sess.scanTargets(sess.modelValue.rValue, columns)
 
 	//获取记录集
 	rows.Next()
@@ -53,6 +71,129 @@ func (sess *Session) ScanModel(rows *sql.Rows) (err error) {
 		}
 		return
 	}
+	//给嵌套的结构体赋值
+	fillNested(sess.modelValue.rValue, nested)
 
 	return
 }
+
+// Scan 将任意查询的记录集赋值到模型，dest必须是结构体或结构体slice的内存地址
+// 按结果集的字段名与模型字段的标记对应，可以用于手写的SQL语句，不需要通过会话构建
+func (instance *Instance) Scan(rows *sql.Rows, dest interface{}) error {
+	rType := reflect.TypeOf(dest)
+	if rType == nil || rType.Kind() != reflect.Ptr {
+		rows.Close()
+		return errors.New("Scan()的dest参数必须是结构体或结构体slice的内存地址")
+	}
+	rType = rType.Elem()
+	if rType.Kind() == reflect.Slice {
+		rType = rType.Elem()
+	}
+	if rType.Kind() != reflect.Struct {
+		rows.Close()
+		return errors.New("Scan()的dest参数必须是结构体或结构体slice的内存地址")
+	}
+	sess := instance.Select(dest)
+	sess.parseModel()
+	if sess.modelValue.isSlice == true {
+		return sess.ScanModelSlice(rows)
+	}
+	return sess.ScanModel(rows)
+}
+
+//一行记录中嵌套结构体的Scan载体
+type nestedHolder struct {
+	field   *nestedField    //嵌套的结构体字段
+	columns []string        //嵌套结构体的字段名
+	holders []reflect.Value //每个字段的载体，类型是**T，值为NULL时*T为nil
+}
+
+//取得一行模型实例的Scan目标
+//嵌套结构体的字段先Scan到载体中，Scan之后再用fillNested()赋值，以便LEFT JOIN没有匹配到记录时能够保持结构体指针为nil
+func (sess *Session) scanTargets(row reflect.Value, columns []string) ([]interface{}, []*nestedHolder) {
+	pointers := sess.rowPointers(row, columns)
+	if len(sess.modelInfo.nested) == 0 {
+		return pointers, nil
+	}
+	var holders []*nestedHolder
+	for i, column := range columns {
+		//模型自身的字段名中可以有__，不是嵌套结构体的字段
+		if _, ok := sess.modelInfo.fields[column]; ok == true {
+			continue
+		}
+		qualifier, name := splitColumn(column)
+		if qualifier == "" {
+			continue
+		}
+		nested := sess.nestedOf(qualifier)
+		if nested == nil {
+			continue
+		}
+		field, ok := nested.info.fields[name]
+		if ok == false {
+			continue
+		}
+		//同一个嵌套结构体的字段使用同一个载体
+		var holder *nestedHolder
+		for _, v := range holders {
+			if v.field == nested {
+				holder = v
+				break
+			}
+		}
+		if holder == nil {
+			holder = &nestedHolder{field: nested}
+			holders = append(holders, holder)
+		}
+		value := reflect.New(reflect.PtrTo(nested.rType.FieldByIndex(field.index).Type))
+		holder.columns = append(holder.columns, name)
+		holder.holders = append(holder.holders, value)
+		pointers[i] = value.Interface()
+	}
+	return pointers, holders
+}
+
+//把载体中的值赋值给嵌套的结构体，结构体指针的字段值全部为NULL时保持为nil
+func fillNested(row reflect.Value, holders []*nestedHolder) {
+	for _, holder := range holders {
+		allNull := true
+		for _, v := range holder.holders {
+			if v.Elem().IsNil() == false {
+				allNull = false
+				break
+			}
+		}
+		target := row.FieldByIndex(holder.field.index)
+		if holder.field.isPtr == true {
+			if allNull == true {
+				target.Set(reflect.Zero(target.Type()))
+				continue
+			}
+			if target.IsNil() == true {
+				target.Set(reflect.New(holder.field.rType))
+			}
+			target = target.Elem()
+		}
+		for k, v := range holder.holders {
+			field := target.FieldByIndex(holder.field.info.fields[holder.columns[k]].index)
+			if v.Elem().IsNil() == true {
+				field.Set(reflect.Zero(field.Type()))
+			} else {
+				field.Set(v.Elem().Elem())
+			}
+		}
+	}
+}
+
+// ScanValues 将单条记录的各字段依次赋值到传入的变量，用于读取聚合函数等标量结果
+// dest必须是变量的内存地址，数量和顺序与结果集的字段一致
+func (sess *Session) ScanValues(rows *sql.Rows, dest ...interface{}) (err error) {
+	defer rows.Close()
+	if rows.Next() == false {
+		if err = rows.Err(); err != nil {
+			return
+		}
+		return sql.ErrNoRows
+	}
+	return rows.Scan(dest...)
+}
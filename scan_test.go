@@ -0,0 +1,157 @@
+package mysqlib
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+// userLogin 定义字段名中有__的模型，profile__avatar与嵌套结构体的“标记__字段名”别名相同
+type userLogin struct {
+	tableName     struct{} `sql:"user"`
+	ID            int64    `sql:"id"`
+	ProfileAvatar string   `sql:"profile__avatar"`
+	LastLogin     string   `sql:"last__login"`
+	Profile       *Profile `sql:"profile"`
+}
+
+// TestScanTargets 测试结果集的字段名对应的模型字段
+func TestScanTargets(t *testing.T) {
+	var row userLogin
+	sess := New().Select(&row).
+		As("u").
+		Column("u.id", "u.profile__avatar", "p.avatar", "u.last__login").
+		LeftJoin(&Profile{}, "p", "u.id = p.user_id")
+	if _, err := sess.Build(false); err != nil {
+		t.Error(err.Error())
+		return
+	}
+	cases := []struct {
+		name   string //结果集的字段名
+		expect string //对应的模型字段，嵌套结构体的字段为“结构体.字段”，为空表示丢弃
+	}{
+		{"id", "ID"},
+		{"profile__avatar", "ProfileAvatar"},
+		{"avatar", "Profile.Avatar"},
+		{"last__login", "LastLogin"},
+		{"p__user_id", "Profile.UserID"},
+		{"username", ""},
+	}
+	names := make([]string, len(cases))
+	for k, c := range cases {
+		names[k] = c.name
+	}
+	columns, err := sess.matchColumns(names)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	rValue := reflect.ValueOf(&row).Elem()
+	pointers, holders := sess.scanTargets(rValue, columns)
+	for k, c := range cases {
+		var result string
+		for _, holder := range holders {
+			for i, v := range holder.holders {
+				if v.Interface() == pointers[k] {
+					result = rValue.Type().FieldByIndex(holder.field.index).Name + "." +
+						holder.field.rType.FieldByIndex(holder.field.info.fields[holder.columns[i]].index).Name
+				}
+			}
+		}
+		for i := 0; i < rValue.NumField(); i++ {
+			//跳过未导出的tableName字段
+			if rValue.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			if rValue.Field(i).Addr().Interface() == pointers[k] {
+				result = rValue.Type().Field(i).Name
+			}
+		}
+		if result != c.expect {
+			t.Errorf("结果集的字段%s对应的模型字段为%q，应为%q", c.name, result, c.expect)
+		}
+	}
+
+	// 启用StrictScan时，结果集中有模型里没有的字段则返回错误
+	sess.builder.options.StrictScan = true
+	if _, err = sess.matchColumns(names); err == nil {
+		t.Error("没有检查出模型中不存在的字段username")
+	}
+	if _, err = sess.matchColumns(names[:5]); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+// TestScanNested 测试JOIN查询的记录赋值到嵌套的结构体，LEFT JOIN没有匹配到记录时结构体指针为nil
+func TestScanNested(t *testing.T) {
+	db, _ := openFakeDB(t, &fakeRows{
+		columns: []string{"id", "username", "user_id", "avatar"},
+		rows: [][]driver.Value{
+			{int64(1), []byte("dxvgef"), int64(1), []byte("a.png")},
+			{int64(2), []byte("guest"), nil, nil},
+		},
+	})
+	var users []UserProfile
+	sess, err := New().Select(&users).
+		As("u").
+		LeftJoin(&Profile{}, "p", "u.id = p.user_id").
+		Build(false)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	rows, err := db.Query(sess.GetStmt(), sess.GetValues()...)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if err = sess.ScanModelSlice(rows); err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if len(users) != 2 {
+		t.Error("记录数不正确：", len(users))
+		return
+	}
+	if users[0].Username != "dxvgef" || users[0].Profile == nil || *users[0].Profile != (Profile{UserID: 1, Avatar: "a.png"}) {
+		t.Errorf("嵌套的结构体赋值不正确：%+v %+v", users[0], users[0].Profile)
+	}
+	if users[1].Username != "guest" || users[1].Profile != nil {
+		t.Errorf("LEFT JOIN没有匹配到记录时嵌套的结构体指针应为nil：%+v %+v", users[1], users[1].Profile)
+	}
+}
+
+// TestScanRows 测试按结果集的字段名赋值记录，模型自身字段名中的__不会被当作嵌套结构体的前缀
+func TestScanRows(t *testing.T) {
+	db, _ := openFakeDB(t, &fakeRows{
+		columns: []string{"last__login", "profile__user_id", "unknown", "profile__avatar", "id"},
+		rows: [][]driver.Value{
+			{[]byte("2026-10-19"), int64(1), int64(9), []byte("me.png"), int64(1)},
+			{[]byte("2026-10-18"), nil, nil, []byte("guest.png"), int64(2)},
+		},
+	})
+	rows, err := db.Query("SELECT * FROM `user_login`")
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	var users []userLogin
+	if err = New().Scan(rows, &users); err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if len(users) != 2 {
+		t.Error("记录数不正确：", len(users))
+		return
+	}
+	user := users[0]
+	if user.ID != 1 || user.ProfileAvatar != "me.png" || user.LastLogin != "2026-10-19" {
+		t.Errorf("模型的字段赋值不正确：%+v", user)
+	}
+	if user.Profile == nil || *user.Profile != (Profile{UserID: 1}) {
+		t.Errorf("嵌套的结构体赋值不正确：%+v", user.Profile)
+	}
+	if users[1].ProfileAvatar != "guest.png" || users[1].Profile != nil {
+		t.Errorf("嵌套的结构体字段都为NULL时应为nil：%+v", users[1])
+	}
+}
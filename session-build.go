@@ -3,14 +3,15 @@ package mysqlib
 import (
 	"bytes"
 	"errors"
+	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
-type keyString struct {
-	key   string
-	value string
-}
+//每条语句中?占位符数量的上限
+const maxPlaceholders = 65535
 
 // Build 开始构建语句，并赋值会话实例及错误消息
 // 入参值为true时，构建可直接执行含有参数值的SQL语句
@@ -20,8 +21,19 @@ func (sess *Session) Build(final bool) (*Session, error) {
 	if sess.err != nil {
 		return nil, sess.err
 	}
+	//会话正在构建时又被构建，说明UNION、WITH、子查询等引用的会话之间存在循环
+	if sess.stmt.building == true {
+		return nil, errors.New("会话之间存在循环引用")
+	}
+	sess.stmt.building = true
+	defer func() {
+		sess.stmt.building = false
+	}()
 
 	var stmt bytes.Buffer
+	//重复构建时清空上次汇总的参数值和构建错误
+	sess.stmt.resultValues = nil
+	sess.stmt.buildErr = nil
 
 	//解析模型结构
 	sess.parseModel()
@@ -31,180 +43,404 @@ func (sess *Session) Build(final bool) (*Session, error) {
 		return nil, errors.New("没有定义表名")
 	}
 
+	//检查标识符和运算符
+	if err := sess.validate(); err != nil {
+		return nil, err
+	}
+
+	//解析游标分页的游标
+	if err := sess.parseCursor(); err != nil {
+		return nil, err
+	}
+
 	//根据行为调用不同的解析方法
 	switch sess.stmt.action {
-	case "INSERT":
-		stmt.WriteString(sess.buildInsert(final))
+	case "INSERT", "REPLACE":
+		value, err := sess.buildBatches(final)
+		if err != nil {
+			return nil, err
+		}
+		stmt.WriteString(value)
 	case "UPDATE":
+		//没有用Column()指定字段时，取得Track()保存的模型快照，同一个会话重复构建时使用已经取得的快照
+		if len(sess.stmt.field) == 0 && sess.snapshot == nil {
+			sess.snapshot = sess.builder.snapshotOf(sess.modelValue.Value)
+		}
+		stmt.WriteString(sess.buildWith(final))
 		value := sess.buildUpdate(final)
 		if value == "" {
-			return nil, errors.New("为了安全，`UPDATE`操作必须使用`Column()`方法指定要更新的字段")
+			//模型快照中的字段都没有被修改
+			if sess.snapshot != nil {
+				return nil, ErrNoChanges
+			}
+			return nil, errors.New("为了安全，`UPDATE`操作必须使用`Column()`方法指定要更新的字段，或者先用`Track()`跟踪模型")
 		}
 		stmt.WriteString(value)
 		//拼接where语句
 		stmt.WriteString(sess.buildWhere(final))
 		//拼接order by语句
-		stmt.WriteString(sess.buildOrderBy())
+		stmt.WriteString(sess.buildOrderBy(final))
 		//拼接limit语句
 		stmt.WriteString(sess.buildLimit())
 	case "SELECT":
-		stmt.WriteString(sess.buildSelect(final))
-		//拼接where语句
-		stmt.WriteString(sess.buildWhere(final))
-		//拼接order by语句
-		stmt.WriteString(sess.buildOrderBy())
-		//拼接limit语句
-		stmt.WriteString(sess.buildLimit())
-		//拼接offset语句
-		stmt.WriteString(sess.buildOffset())
+		stmt.WriteString(sess.buildQuery(final, true))
 	case "DELETE":
-		stmt.WriteString("DELETE FROM `")
-		stmt.WriteString(sess.tableName)
-		stmt.WriteString("`")
+		stmt.WriteString(sess.buildWith(final))
+		stmt.WriteString("DELETE")
+		stmt.WriteString(sess.buildHint())
+		stmt.WriteString(" ")
+		//单表DELETE语法不支持索引提示，使用多表DELETE语法
+		if len(sess.stmt.indexHints) > 0 {
+			stmt.WriteString("`")
+			stmt.WriteString(sess.tableQualifier())
+			stmt.WriteString("` ")
+		}
+		stmt.WriteString("FROM ")
+		stmt.WriteString(sess.buildTable(final))
 		//拼接where语句
 		stmt.WriteString(sess.buildWhere(final))
 		//拼接order by语句
-		stmt.WriteString(sess.buildOrderBy())
+		stmt.WriteString(sess.buildOrderBy(final))
 		//拼接limit语句
 		stmt.WriteString(sess.buildLimit())
 	default:
 		return nil, errors.New("未知的行为")
 	}
 
+	//构建过程中产生的错误，例如无法编码的参数值
+	if sess.stmt.buildErr != nil {
+		return nil, sess.stmt.buildErr
+	}
+
 	sess.stmt.resultString = stmt.String()
 
+	//构建成功后丢弃构建器中的模型快照，快照已经保存在会话中
+	if sess.snapshot != nil {
+		sess.builder.Untrack(sess.modelValue.Value)
+	}
+
 	return sess, nil
 }
 
-//拼接INSERT语句
-func (sess *Session) buildInsert(final bool) string {
-	var stmt bytes.Buffer
-	stmt.WriteString("INSERT INTO `")
-	stmt.WriteString(sess.tableName)
-	stmt.WriteString("` (")
-
-	var allField []keyInterface
+//拼接INSERT语句，如果模型是slice，则按BatchSize()和占位符数量上限分块成多条语句
+func (sess *Session) buildBatches(final bool) (string, error) {
+	//INSERT ... SELECT只有一条语句
+	if sess.stmt.fromSelect != nil {
+		return sess.buildInsertSelect(final)
+	}
 
-	// ------------------ 拼接column部分 ----------------------------
-	// 如果没有用Column()指定field
-	var field keyInterface
-	if len(sess.stmt.field) == 0 {
-		//把模型里所有的字段及其值写入到allField里
-		for _, v := range sess.modelInfo.fields {
-			value := sess.modelValue.rValue.FieldByName(v.VarName).Interface()
-			if final == false {
-				sess.stmt.resultValues = append(sess.stmt.resultValues, value)
-			}
-			field.key = v.SQLName
-			field.value = value
-			allField = append(allField, field)
+	//要插入的记录
+	var rows []reflect.Value
+	if sess.modelValue.isSlice == true {
+		rowCount := sess.modelValue.rValue.Len()
+		if rowCount == 0 {
+			return "", errors.New("没有要插入的记录")
+		}
+		for i := 0; i < rowCount; i++ {
+			rows = append(rows, sess.modelValue.rValue.Index(i))
 		}
 	} else {
-		//如果用Column()指定了field
-		for _, sqlName := range sess.stmt.field {
-			value := sess.modelValue.rValue.FieldByName(sess.modelInfo.fields[sqlName.key].VarName).Interface()
-			if final == false {
-				sess.stmt.resultValues = append(sess.stmt.resultValues, value)
+		rows = append(rows, sess.modelValue.rValue)
+	}
+
+	//每条语句最多包含的记录数
+	batchSize := sess.stmt.batchSize
+	if batchSize <= 0 {
+		batchSize = sess.builder.options.MaxBatchRows
+	}
+	//占位符模式下每条语句的?占位符数量不能超过上限
+	columnCount := len(sess.insertColumns()) + len(sess.stmt.addValue)
+	if columnCount == 0 {
+		return "", errors.New("没有要插入的字段")
+	}
+	//每条记录的占位符数量，原生语句片段按其参数数量计算
+	rowPlaceholders := len(sess.insertColumns())
+	for _, v := range sess.stmt.addValue {
+		if raw, ok := v.value.(*rawExpr); ok == true {
+			rowPlaceholders += len(raw.args)
+		} else {
+			rowPlaceholders++
+		}
+	}
+	if final == false {
+		//ON DUPLICATE KEY UPDATE子句中的参数也要计入占位符数量
+		duplicateCount := 0
+		for _, v := range sess.stmt.duplicate {
+			if v.mode == "SET" || v.mode == "INCR" {
+				duplicateCount++
 			}
-			field.key = sqlName.key
-			field.value = value
-			allField = append(allField, field)
+		}
+		maxRows := maxPlaceholders - duplicateCount
+		if rowPlaceholders > 0 {
+			maxRows /= rowPlaceholders
+		}
+		if batchSize <= 0 || batchSize > maxRows {
+			batchSize = maxRows
 		}
 	}
+	if batchSize <= 0 {
+		batchSize = len(rows)
+	}
 
-	//把额外添加的字段也汇总到allField
-	for _, v := range sess.stmt.addValue {
-		if final == false {
-			sess.stmt.resultValues = append(sess.stmt.resultValues, v.value)
+	//分块构建语句
+	sess.stmt.batches = nil
+	for start := 0; start < len(rows); start += batchSize {
+		end := start + batchSize
+		if end > len(rows) {
+			end = len(rows)
 		}
-		field.key = v.key
-		field.value = v.value
-		allField = append(allField, field)
+		sess.stmt.resultValues = nil
+		var batch Batch
+		batch.sess = sess
+		batch.rows = rows[start:end]
+		batch.Stmt = sess.buildInsert(final, batch.rows) + ";"
+		batch.Values = sess.stmt.resultValues
+		sess.stmt.batches = append(sess.stmt.batches, &batch)
 	}
 
-	// 拼接column部分
-	for k, v := range allField {
+	//GetStmt()和GetValues()返回第一条语句
+	sess.stmt.resultValues = sess.stmt.batches[0].Values
+	return sess.stmt.batches[0].Stmt, nil
+}
 
-		// 拼接语句
+//拼接INSERT ... SELECT语句
+func (sess *Session) buildInsertSelect(final bool) (string, error) {
+	if len(sess.stmt.addValue) > 0 {
+		return "", errors.New("`FromSelect()`不能与`AddValue()`同时使用")
+	}
+	if sess.stmt.rowAlias != "" {
+		return "", errors.New("`FromSelect()`不能与`RowAlias()`同时使用")
+	}
+	if len(sess.insertColumns()) == 0 {
+		return "", errors.New("没有要插入的字段")
+	}
+	//构建SELECT会话
+	if _, err := sess.stmt.fromSelect.Build(final); err != nil {
+		return "", err
+	}
+	sess.stmt.resultValues = nil
+	var batch Batch
+	batch.sess = sess
+	batch.Stmt = sess.buildInsert(final, nil) + ";"
+	batch.Values = sess.stmt.resultValues
+	sess.stmt.batches = []*Batch{&batch}
+	return batch.Stmt, nil
+}
+
+//INSERT要插入的模型字段
+func (sess *Session) insertColumns() []string {
+	//如果用Column()指定了field
+	if len(sess.stmt.field) > 0 {
+		return sess.fieldKeys()
+	}
+	//如果没有用Column()指定field，则使用模型里所有的字段
+	return sess.modelInfo.columns
+}
+
+//拼接一条INSERT语句，rows是本条语句要插入的记录
+func (sess *Session) buildInsert(final bool, rows []reflect.Value) string {
+	var stmt bytes.Buffer
+	stmt.WriteString(sess.stmt.action)
+	stmt.WriteString(sess.buildHint())
+	if sess.stmt.ignore == true {
+		stmt.WriteString(" IGNORE")
+	}
+	stmt.WriteString(" INTO `")
+	stmt.WriteString(sess.tableName)
+	stmt.WriteString("` (")
+
+	// ------------------ 拼接column部分 ----------------------------
+	columns := sess.insertColumns()
+	for k, v := range columns {
 		if k == 0 {
 			stmt.WriteString("`")
-			stmt.WriteString(v.key)
+			stmt.WriteString(v)
 			stmt.WriteString("`")
 		} else {
 			stmt.WriteString(", `")
-			stmt.WriteString(v.key)
+			stmt.WriteString(v)
+			stmt.WriteString("`")
+		}
+	}
+	//把额外添加的字段也拼接上
+	for k, v := range sess.stmt.addValue {
+		if k == 0 && len(columns) == 0 {
 			stmt.WriteString("`")
+		} else {
+			stmt.WriteString(", `")
 		}
+		stmt.WriteString(v.key)
+		stmt.WriteString("`")
 	}
+	stmt.WriteString(")")
+
+	// ------------------ 拼接SELECT部分 ----------------------------
+	if sess.stmt.fromSelect != nil {
+		stmt.WriteString(" ")
+		stmt.WriteString(sess.stmt.fromSelect.stmt.resultString)
+		if final == false {
+			sess.stmt.resultValues = append(sess.stmt.resultValues, sess.stmt.fromSelect.stmt.resultValues...)
+		}
+		stmt.WriteString(sess.buildDuplicate(final))
+		return stmt.String()
+	}
+
 	//VALUES前面的拼接完成
-	stmt.WriteString(") VALUES (")
-
-	//遍历开始拼接参数值
-	if final == true {
-		for k, v := range allField {
-			value, ok := v.value.(string)
-			if k == 0 {
-				if ok == true {
-					stmt.WriteString("'")
-					stmt.WriteString(value)
-					stmt.WriteString("'")
-				} else {
-					stmt.WriteString(interfaceToString(v.value))
-				}
-			} else {
-				if ok == true {
-					stmt.WriteString(", '")
-					stmt.WriteString(value)
-					stmt.WriteString("'")
-				} else {
-					stmt.WriteString(", ")
-					stmt.WriteString(interfaceToString(v.value))
-				}
+	stmt.WriteString(" VALUES ")
+
+	// ------------------ 拼接VALUES部分 ----------------------------
+	for i, row := range rows {
+		if i > 0 {
+			stmt.WriteString(", ")
+		}
+		stmt.WriteString("(")
+		values := sess.rowValues(row, columns)
+		//额外添加的字段在每条记录中的值都一样
+		for _, v := range sess.stmt.addValue {
+			values = append(values, v.value)
+		}
+		for k, v := range values {
+			if k > 0 {
+				stmt.WriteString(", ")
 			}
+			sess.writeValue(&stmt, v, final)
 		}
-	} else {
-		fieldCount := len(allField)
-		for i := 0; i < fieldCount; i++ {
-			if i == 0 {
-				stmt.WriteString("?")
+		stmt.WriteString(")")
+	}
+
+	//行别名
+	if sess.stmt.rowAlias != "" {
+		stmt.WriteString(" AS `")
+		stmt.WriteString(sess.stmt.rowAlias)
+		stmt.WriteString("`")
+	}
+
+	//拼接ON DUPLICATE KEY UPDATE语句
+	stmt.WriteString(sess.buildDuplicate(final))
+
+	return stmt.String()
+}
+
+//拼接ON DUPLICATE KEY UPDATE语句
+func (sess *Session) buildDuplicate(final bool) string {
+	if len(sess.stmt.duplicate) == 0 {
+		return ""
+	}
+	var stmt bytes.Buffer
+	stmt.WriteString(" ON DUPLICATE KEY UPDATE ")
+	for k, v := range sess.stmt.duplicate {
+		if k > 0 {
+			stmt.WriteString(", ")
+		}
+		stmt.WriteString("`")
+		stmt.WriteString(v.field)
+		stmt.WriteString("`=")
+		switch v.mode {
+		case "VALUES":
+			//有行别名时使用别名引用要插入的值
+			if sess.stmt.rowAlias != "" {
+				stmt.WriteString("`")
+				stmt.WriteString(sess.stmt.rowAlias)
+				stmt.WriteString("`.`")
+				stmt.WriteString(v.field)
+				stmt.WriteString("`")
 			} else {
-				stmt.WriteString(", ?")
+				stmt.WriteString("VALUES(`")
+				stmt.WriteString(v.field)
+				stmt.WriteString("`)")
 			}
+		case "SET":
+			sess.writeValue(&stmt, v.value, final)
+		case "EXPR":
+			stmt.WriteString(v.value.(string))
+		case "INCR":
+			stmt.WriteString("`")
+			stmt.WriteString(v.field)
+			stmt.WriteString("`+")
+			sess.writeValue(&stmt, v.value, final)
 		}
 	}
+	return stmt.String()
+}
+
+//拼接参数值
+//final为true时拼接参数值本身，否则拼接?占位符并把参数值汇总到resultValues
+func (sess *Session) writeValue(stmt *bytes.Buffer, value interface{}, final bool) {
+	//原生语句片段
+	if raw, ok := value.(*rawExpr); ok == true {
+		sess.writeRaw(stmt, raw, final)
+		return
+	}
+	//子查询
+	if sub, ok := value.(*Session); ok == true {
+		stmt.WriteString("(")
+		stmt.WriteString(sess.buildSubquery(sub, final))
+		stmt.WriteString(")")
+		return
+	}
+	if final == false {
+		stmt.WriteString("?")
+		sess.stmt.resultValues = append(sess.stmt.resultValues, value)
+		return
+	}
+	literal, err := encodeValue(value, sess.builder.options)
+	if err != nil {
+		sess.buildError(err)
+		return
+	}
+	stmt.WriteString(literal)
+}
 
-	stmt.WriteString(");")
+//记录构建过程中产生的错误，只保留第一个，由本次构建返回，不影响会话之后的构建
+func (sess *Session) buildError(err error) {
+	if sess.stmt.buildErr == nil {
+		sess.stmt.buildErr = err
+	}
+}
 
-	return stmt.String()
+//拼接原生语句片段，语句中的?占位符依次替换成参数
+//final为false时保留?占位符，参数按出现的顺序汇总到resultValues
+func (sess *Session) writeRaw(stmt *bytes.Buffer, raw *rawExpr, final bool) {
+	parts, open := scanRaw(raw.stmt)
+	for k, v := range parts {
+		if k > 0 {
+			sess.writeValue(stmt, raw.args[k-1], final)
+		}
+		stmt.WriteString(v)
+	}
+	//语句以单行注释结尾时换行，否则后面拼接的内容会成为注释
+	if open == '\n' {
+		stmt.WriteString("\n")
+	}
 }
 
 //拼接UPDATE语句
 func (sess *Session) buildUpdate(final bool) string {
 	var stmt bytes.Buffer
-	stmt.WriteString("UPDATE `")
-	stmt.WriteString(sess.tableName)
-	stmt.WriteString("` SET ")
+	stmt.WriteString("UPDATE")
+	stmt.WriteString(sess.buildHint())
+	stmt.WriteString(" ")
+	stmt.WriteString(sess.buildTable(final))
+	stmt.WriteString(" SET ")
 
 	var allField []keyInterface
 
 	// ------------------ 拼接SET部分 ----------------------------
+	columns := sess.fieldKeys()
+	//没有用Column()指定字段时，只更新Track()之后被修改的字段
+	if len(columns) == 0 && sess.snapshot != nil {
+		columns = sess.changedColumns()
+	}
 	var field keyInterface
 	//遍历Column
-	for _, sqlName := range sess.stmt.field {
-		value := sess.modelValue.rValue.FieldByName(sess.modelInfo.fields[sqlName.key].VarName).Interface()
-		if final == false {
-			sess.stmt.resultValues = append(sess.stmt.resultValues, value)
-		}
-		field.key = sqlName.key
+	for k, value := range sess.rowValues(sess.modelValue.rValue, columns) {
+		field.key = columns[k]
 		field.value = value
 		allField = append(allField, field)
 	}
 
 	//把额外添加的字段也汇总到allField
 	for _, v := range sess.stmt.addValue {
-		if final == false {
-			sess.stmt.resultValues = append(sess.stmt.resultValues, v.value)
-		}
 		field.key = v.key
 		field.value = v.value
 		allField = append(allField, field)
@@ -214,276 +450,498 @@ func (sess *Session) buildUpdate(final bool) string {
 	}
 	// 拼接set语句
 	for k, v := range allField {
-		value, ok := v.value.(string)
-		if final == true {
-			if k == 0 {
-				if ok == true {
-					stmt.WriteString("`")
-					stmt.WriteString(v.key)
-					stmt.WriteString("`='")
-					stmt.WriteString(value)
-					stmt.WriteString("'")
-				} else {
-					stmt.WriteString("`")
-					stmt.WriteString(v.key)
-					stmt.WriteString("`=")
-					stmt.WriteString(interfaceToString(v.value))
-				}
-			} else {
-				if ok == true {
-					stmt.WriteString(", `")
-					stmt.WriteString(v.key)
-					stmt.WriteString("`='")
-					stmt.WriteString(value)
-					stmt.WriteString("'")
-				} else {
-					stmt.WriteString(", `")
-					stmt.WriteString(v.key)
-					stmt.WriteString("`=")
-					stmt.WriteString(interfaceToString(v.value))
-				}
-			}
+		if k > 0 {
+			stmt.WriteString(", ")
+		}
+		stmt.WriteString("`")
+		stmt.WriteString(v.key)
+		stmt.WriteString("`=")
+		sess.writeValue(&stmt, v.value, final)
+	}
+
+	return stmt.String()
+}
+
+//拼接完整的SELECT语句，sort为false时不拼接ORDER BY/LIMIT/OFFSET，用于统计记录数等场景
+//有UNION时，ORDER BY/LIMIT/OFFSET作用于合并后的结果
+func (sess *Session) buildQuery(final, sort bool) string {
+	var stmt bytes.Buffer
+	stmt.WriteString(sess.buildWith(final))
+	stmt.WriteString(sess.buildSelect(final))
+	//拼接where语句，游标分页的条件与排序相关，不排序时不需要
+	if sort == true && sess.stmt.keyset.values != nil {
+		stmt.WriteString(sess.buildKeysetWhere(final))
+	} else {
+		stmt.WriteString(sess.buildWhere(final))
+	}
+	//拼接group by语句
+	stmt.WriteString(sess.buildGroupBy())
+	//拼接having语句
+	stmt.WriteString(sess.buildHaving(final))
+	//拼接union语句
+	stmt.WriteString(sess.buildUnion(final))
+	if sort == false {
+		return stmt.String()
+	}
+	//拼接order by语句
+	stmt.WriteString(sess.buildOrderBy(final))
+	//拼接limit语句
+	stmt.WriteString(sess.buildLimit())
+	//拼接offset语句
+	stmt.WriteString(sess.buildOffset())
+	//拼接锁定读语句
+	stmt.WriteString(sess.buildLock())
+	return stmt.String()
+}
+
+//拼接锁定读语句
+func (sess *Session) buildLock() string {
+	if sess.stmt.lock.mode == "" {
+		return ""
+	}
+	var stmt bytes.Buffer
+	stmt.WriteString(" ")
+	stmt.WriteString(sess.stmt.lock.mode)
+	for k, v := range sess.stmt.lock.tables {
+		if k == 0 {
+			stmt.WriteString(" OF ")
 		} else {
-			if k == 0 {
-				stmt.WriteString("`")
-				stmt.WriteString(v.key)
-				stmt.WriteString("`=?")
-			} else {
-				stmt.WriteString(", `")
-				stmt.WriteString(v.key)
-				stmt.WriteString("`=?")
-			}
+			stmt.WriteString(", ")
+		}
+		stmt.WriteString("`")
+		stmt.WriteString(v)
+		stmt.WriteString("`")
+	}
+	if sess.stmt.lock.wait != "" {
+		stmt.WriteString(" ")
+		stmt.WriteString(sess.stmt.lock.wait)
+	}
+	return stmt.String()
+}
+
+//拼接WITH语句，公用表表达式的会话各自构建后用括号包裹
+func (sess *Session) buildWith(final bool) string {
+	if len(sess.stmt.ctes) == 0 {
+		return ""
+	}
+	var stmt bytes.Buffer
+	stmt.WriteString("WITH ")
+	if sess.stmt.recursive == true {
+		stmt.WriteString("RECURSIVE ")
+	}
+	for k, v := range sess.stmt.ctes {
+		if k > 0 {
+			stmt.WriteString(", ")
+		}
+		stmt.WriteString("`")
+		stmt.WriteString(v.name)
+		stmt.WriteString("`")
+		if len(v.columns) > 0 {
+			stmt.WriteString(" (`")
+			stmt.WriteString(strings.Join(v.columns, "`, `"))
+			stmt.WriteString("`)")
 		}
+		stmt.WriteString(" AS (")
+		stmt.WriteString(sess.buildSubquery(v.sess, final))
+		stmt.WriteString(")")
 	}
+	stmt.WriteString(" ")
+	return stmt.String()
+}
 
+//拼接UNION语句，要合并的会话有自己的ORDER BY/LIMIT/OFFSET或锁定读时用括号包裹
+func (sess *Session) buildUnion(final bool) string {
+	var stmt bytes.Buffer
+	for _, v := range sess.stmt.unions {
+		wrap := len(v.sess.stmt.orders) > 0 || v.sess.stmt.limit > 0 || v.sess.stmt.offset > 0 || v.sess.stmt.lock.mode != ""
+		stmt.WriteString(" UNION ")
+		if v.all == true {
+			stmt.WriteString("ALL ")
+		}
+		if wrap == true {
+			stmt.WriteString("(")
+		}
+		stmt.WriteString(sess.buildSubquery(v.sess, final))
+		if wrap == true {
+			stmt.WriteString(")")
+		}
+	}
 	return stmt.String()
 }
 
+//构建子查询会话，返回其语句，参数值按顺序汇总到本会话中
+//UNION、WITH、WHERE条件、派生表中的子查询都通过此方法构建，子查询的错误由本会话的Build()返回
+func (sess *Session) buildSubquery(sub *Session, final bool) string {
+	if sess.err != nil {
+		return ""
+	}
+	if sub == sess {
+		sess.buildError(errors.New("子查询不能是会话本身"))
+		return ""
+	}
+	if sub.stmt.action != "SELECT" {
+		sess.buildError(errors.New("子查询必须是SELECT会话"))
+		return ""
+	}
+	if _, err := sub.Build(final); err != nil {
+		sess.buildError(err)
+		return ""
+	}
+	if final == false {
+		sess.stmt.resultValues = append(sess.stmt.resultValues, sub.stmt.resultValues...)
+	}
+	return sub.stmt.resultString
+}
+
 //拼接SELECT语句
 func (sess *Session) buildSelect(final bool) string {
 	var stmt bytes.Buffer
-	stmt.WriteString("SELECT ")
-
-	var allField []keyString
+	stmt.WriteString("SELECT")
+	stmt.WriteString(sess.buildHint())
+	stmt.WriteString(" ")
 
 	// ------------------ 拼接column部分 ----------------------------
-	// 如果没有用Column()指定field也没有用ColumnRaw()指定过字段
-	var field keyString
+	// 如果没有用Column()指定field，则把模型里所有的字段写入到sess.stmt.field，Scan时按此顺序赋值
 	if len(sess.stmt.field) == 0 {
-		//把模型里所有的字段及其值写入到allField里
-		for _, v := range sess.modelInfo.fields {
-			field.key = v.SQLName
-			if sess.modelValue.isSlice == false {
-				field.value = interfaceToString(sess.modelValue.rValue.FieldByName(v.VarName).Interface())
-			}
+		//使用JOIN时，给字段加上主表的别名或表名，避免与其它表的字段重名
+		var qualifier string
+		if len(sess.stmt.joins) > 0 {
+			qualifier = sess.tableQualifier() + "."
+		}
+		for _, column := range sess.modelInfo.columns {
 			sess.stmt.field = append(sess.stmt.field, &keyInterface{
-				key:   field.key,
-				value: field.value,
+				key: qualifier + column,
 			})
-			//将k/v插入到allField
-			allField = append(allField, field)
 		}
-	} else {
-		//如果用Column()指定了field
-		for _, sqlName := range sess.stmt.field {
-			field.key = sqlName.key
-			if sess.modelValue.isSlice == false {
-				field.value = interfaceToString(sess.modelValue.rValue.FieldByName(sess.modelInfo.fields[sqlName.key].VarName).Interface())
+		//如果JOIN的表对应模型中嵌套的结构体，则把嵌套结构体的字段也写入
+		for _, j := range sess.stmt.joins {
+			joinQualifier := j.alias
+			if joinQualifier == "" {
+				joinQualifier = j.table
+			}
+			nested := sess.nestedOf(joinQualifier)
+			if nested == nil {
+				continue
+			}
+			for _, column := range nested.info.columns {
+				sess.stmt.field = append(sess.stmt.field, &keyInterface{
+					key: joinQualifier + "." + column,
+				})
 			}
-			allField = append(allField, field)
 		}
 	}
 
-	// 拼接column部分
-	for k, v := range allField {
-		if k == 0 {
-			if v.value == "" {
-				stmt.WriteString("`")
-				stmt.WriteString(v.key)
-				stmt.WriteString("`")
-			} else {
-				stmt.WriteString("`")
-				stmt.WriteString(v.key)
-				stmt.WriteString("`")
-			}
-		} else {
-			if v.value == "" {
-				stmt.WriteString(", ")
-				stmt.WriteString("`")
-				stmt.WriteString(v.key)
-				stmt.WriteString("`")
-			} else {
-				stmt.WriteString(", `")
-				stmt.WriteString(v.key)
-				stmt.WriteString("`")
-			}
+	// 拼接column部分，没有模型也没有用Column()指定字段时查询所有字段
+	if len(sess.stmt.field) == 0 {
+		stmt.WriteString("*")
+	}
+	for k, v := range sess.stmt.field {
+		if k > 0 {
+			stmt.WriteString(", ")
+		}
+		//聚合函数等表达式的value是表达式，key是别名
+		if expr, ok := v.value.(string); ok == true {
+			stmt.WriteString(expr)
+			stmt.WriteString(" AS ")
 		}
+		//ColumnRaw()传入的原生语句，key是别名
+		if raw, ok := v.value.(*rawExpr); ok == true {
+			sess.writeRaw(&stmt, raw, final)
+			stmt.WriteString(" AS ")
+		}
+		stmt.WriteString(quoteField(v.key))
 	}
 
 	//VALUES前面的拼接完成
-	stmt.WriteString(" FROM `")
+	stmt.WriteString(" FROM ")
+	stmt.WriteString(sess.buildTable(final))
+
+	//拼接JOIN语句
+	for _, v := range sess.stmt.joins {
+		stmt.WriteString(" ")
+		stmt.WriteString(v.kind)
+		stmt.WriteString(" `")
+		stmt.WriteString(v.table)
+		stmt.WriteString("`")
+		if v.alias != "" {
+			stmt.WriteString(" AS `")
+			stmt.WriteString(v.alias)
+			stmt.WriteString("`")
+		}
+		stmt.WriteString(" ON ")
+		stmt.WriteString(buildOn(v.on))
+	}
+
+	return stmt.String()
+}
+
+//拼接主表名及其别名和索引提示，主表是派生表时拼接子查询及其别名
+func (sess *Session) buildTable(final bool) string {
+	var stmt bytes.Buffer
+	if sess.stmt.from != nil {
+		stmt.WriteString("(")
+		stmt.WriteString(sess.buildSubquery(sess.stmt.from, final))
+		stmt.WriteString(") AS `")
+		stmt.WriteString(sess.tableName)
+		stmt.WriteString("`")
+		return stmt.String()
+	}
+	stmt.WriteString("`")
 	stmt.WriteString(sess.tableName)
 	stmt.WriteString("`")
-
+	if sess.alias != "" {
+		stmt.WriteString(" AS `")
+		stmt.WriteString(sess.alias)
+		stmt.WriteString("`")
+	}
+	for _, v := range sess.stmt.indexHints {
+		stmt.WriteString(" ")
+		stmt.WriteString(v.kind)
+		stmt.WriteString(" INDEX (")
+		for k, name := range v.names {
+			if k > 0 {
+				stmt.WriteString(", ")
+			}
+			stmt.WriteString("`")
+			stmt.WriteString(name)
+			stmt.WriteString("`")
+		}
+		stmt.WriteString(")")
+	}
 	return stmt.String()
 }
 
+//拼接优化器提示，紧跟在SELECT/INSERT/UPDATE/DELETE关键字后面
+func (sess *Session) buildHint() string {
+	if len(sess.stmt.hints) == 0 {
+		return ""
+	}
+	return " /*+ " + strings.Join(sess.stmt.hints, " ") + " */"
+}
+
+//引用主表字段时使用的限定名，有别名时使用别名，否则使用表名
+func (sess *Session) tableQualifier() string {
+	if sess.alias != "" {
+		return sess.alias
+	}
+	return sess.tableName
+}
+
+//JOIN连接条件中形如"字段 运算符 字段"的格式
+var onPattern = regexp.MustCompile(`^\s*([\w.]+)\s*(=|<>|!=|<=>|<=|>=|<|>)\s*([\w.]+)\s*$`)
+
+//拼接JOIN的连接条件，形如"字段 运算符 字段"的条件会给字段加上反引号，否则原样返回
+func buildOn(on string) string {
+	matches := onPattern.FindStringSubmatch(on)
+	if matches == nil {
+		return on
+	}
+	return quoteField(matches[1]) + " " + matches[2] + " " + quoteField(matches[3])
+}
+
 //构建where语句
 func (sess *Session) buildWhere(final bool) string {
 	if sess.err != nil {
 		return ""
 	}
-	whereCount := len(sess.stmt.where)
-	if whereCount == 0 {
+	if len(sess.stmt.where.conds) == 0 {
+		return ""
+	}
+	return " WHERE " + sess.buildConds(sess.stmt.where.conds, final)
+}
+
+//构建GROUP BY语句
+func (sess *Session) buildGroupBy() string {
+	if sess.err != nil {
+		return ""
+	}
+	if len(sess.stmt.groups) == 0 {
+		return ""
+	}
+	var stmt bytes.Buffer
+	stmt.WriteString(" GROUP BY ")
+	for k, v := range sess.stmt.groups {
+		if k > 0 {
+			stmt.WriteString(", ")
+		}
+		stmt.WriteString(quoteField(v))
+	}
+	return stmt.String()
+}
+
+//构建HAVING语句
+func (sess *Session) buildHaving(final bool) string {
+	if sess.err != nil {
+		return ""
+	}
+	if len(sess.stmt.having) == 0 {
 		return ""
 	}
+	return " HAVING " + sess.buildConds(sess.stmt.having, final)
+}
+
+//构建WHERE或HAVING的条件语句
+func (sess *Session) buildConds(conds []*whereCond, final bool) string {
+	var stmt bytes.Buffer
+	for _, cond := range conds {
+		//跳过没有条件的条件组
+		if cond.group == true && len(cond.children) == 0 {
+			continue
+		}
+		if stmt.Len() > 0 {
+			stmt.WriteString(" ")
+			stmt.WriteString(cond.union)
+			stmt.WriteString(" ")
+		}
+		stmt.WriteString(sess.buildCond(cond, final))
+	}
+	return stmt.String()
+}
+
+//构建单个条件
+func (sess *Session) buildCond(cond *whereCond, final bool) string {
 	var stmt bytes.Buffer
-	stmt.WriteString(" WHERE ")
-
-	//遍历where条件
-	var value bytes.Buffer
-	for i := 0; i < whereCount; i++ {
-		value.Reset()
-		//如果是IN或NOT IN
-		if sess.stmt.where[i].operator == "IN" || sess.stmt.where[i].operator == "NOT IN" {
-			if final == true {
-				tmpValues := sliceToString(sess.stmt.where[i].value)
-				values, ok := sess.stmt.where[i].value.([]string)
-				if ok == true {
-					for k, v := range values {
-						if k == 0 {
-							value.WriteString("`")
-							value.WriteString(v)
-							value.WriteString("`")
-						} else {
-							value.WriteString(", `")
-							value.WriteString(v)
-							value.WriteString("`")
-						}
-					}
-				} else {
-					value.WriteString(strings.Join(tmpValues, ", "))
-				}
-
-				if i == 0 {
-					stmt.WriteString("(`")
-					stmt.WriteString(sess.stmt.where[i].field)
-					stmt.WriteString("` ")
-					stmt.WriteString(sess.stmt.where[i].operator)
-					stmt.WriteString(" (")
-					stmt.WriteString(value.String())
-					stmt.WriteString("))")
-				} else {
-					stmt.WriteString(" ")
-					stmt.WriteString(sess.stmt.where[i].union)
-					stmt.WriteString(" (`")
-					stmt.WriteString(sess.stmt.where[i].field)
-					stmt.WriteString("` ")
-					stmt.WriteString(sess.stmt.where[i].operator)
-					stmt.WriteString(" (")
-					stmt.WriteString(value.String())
-					stmt.WriteString("))")
-				}
+	//条件组，整组用括号包裹
+	if cond.group == true {
+		if cond.not == true {
+			stmt.WriteString("NOT ")
+		}
+		stmt.WriteString("(")
+		stmt.WriteString(sess.buildConds(cond.children, final))
+		stmt.WriteString(")")
+		return stmt.String()
+	}
+	//原生条件语句，?占位符依次替换成参数，用括号包裹以免其中的OR改变前后条件的优先级
+	if cond.raw != nil {
+		stmt.WriteString("(")
+		sess.writeRaw(&stmt, cond.raw, final)
+		stmt.WriteString(")")
+		return stmt.String()
+	}
+	switch cond.operator {
+	//IN或NOT IN，slice中的每个元素对应一个参数
+	case "IN", "NOT IN":
+		//值是子查询
+		if sub, ok := cond.value.(*Session); ok == true {
+			stmt.WriteString("(")
+			stmt.WriteString(quoteField(cond.field))
+			stmt.WriteString(" ")
+			stmt.WriteString(cond.operator)
+			stmt.WriteString(" (")
+			stmt.WriteString(sess.buildSubquery(sub, final))
+			stmt.WriteString("))")
+			break
+		}
+		values := inValues(cond.value)
+		//空列表时IN恒为假，NOT IN恒为真
+		if len(values) == 0 {
+			if cond.operator == "IN" {
+				stmt.WriteString("(1=0)")
 			} else {
-				if i == 0 {
-					stmt.WriteString("(`")
-					stmt.WriteString(sess.stmt.where[i].field)
-					stmt.WriteString("` ")
-					stmt.WriteString(sess.stmt.where[i].operator)
-					stmt.WriteString(" (?))")
-				} else {
-					stmt.WriteString(" ")
-					stmt.WriteString(sess.stmt.where[i].union)
-					stmt.WriteString(" (`")
-					stmt.WriteString(sess.stmt.where[i].field)
-					stmt.WriteString("` ")
-					stmt.WriteString(sess.stmt.where[i].operator)
-					stmt.WriteString(" (?))")
-				}
-				sess.stmt.resultValues = append(sess.stmt.resultValues, sess.stmt.where[i].value)
+				stmt.WriteString("(1=1)")
 			}
-		} else if sess.stmt.where[i].operator == "[!RAW!]" {
-			if i == 0 {
-				stmt.WriteString(sess.stmt.where[i].field)
-			} else {
-				stmt.WriteString(" ")
-				stmt.WriteString(sess.stmt.where[i].union)
-				stmt.WriteString(" ")
-				stmt.WriteString(sess.stmt.where[i].field)
+			break
+		}
+		stmt.WriteString("(")
+		stmt.WriteString(quoteField(cond.field))
+		stmt.WriteString(" ")
+		stmt.WriteString(cond.operator)
+		stmt.WriteString(" (")
+		for k, v := range values {
+			if k > 0 {
+				stmt.WriteString(", ")
 			}
+			sess.writeValue(&stmt, v, final)
+		}
+		stmt.WriteString("))")
+	//BETWEEN或NOT BETWEEN，值是最小值和最大值
+	case "BETWEEN", "NOT BETWEEN":
+		values := inValues(cond.value)
+		if len(values) != 2 {
+			sess.buildError(errors.New("`" + cond.operator + "`条件的值必须是最小值和最大值"))
+			break
+		}
+		stmt.WriteString("(")
+		stmt.WriteString(quoteField(cond.field))
+		stmt.WriteString(" ")
+		stmt.WriteString(cond.operator)
+		stmt.WriteString(" ")
+		sess.writeValue(&stmt, values[0], final)
+		stmt.WriteString(" AND ")
+		sess.writeValue(&stmt, values[1], final)
+		stmt.WriteString(")")
+	//EXISTS或NOT EXISTS，值是子查询
+	case "EXISTS", "NOT EXISTS":
+		sub, ok := cond.value.(*Session)
+		if ok == false || sub == nil {
+			sess.buildError(errors.New("`" + cond.operator + "`条件的值必须是SELECT会话"))
+			break
+		}
+		stmt.WriteString("(")
+		stmt.WriteString(cond.operator)
+		stmt.WriteString(" (")
+		stmt.WriteString(sess.buildSubquery(sub, final))
+		stmt.WriteString("))")
+	//IS NULL或IS NOT NULL，没有值
+	case "IS NULL", "IS NOT NULL":
+		stmt.WriteString("(")
+		stmt.WriteString(quoteField(cond.field))
+		stmt.WriteString(" ")
+		stmt.WriteString(cond.operator)
+		stmt.WriteString(")")
+	default:
+		stmt.WriteString("(")
+		stmt.WriteString(quoteField(cond.field))
+		//LIKE、REGEXP等单词形式的运算符两边加上空格
+		if strings.IndexFunc(cond.operator, unicode.IsLetter) >= 0 {
+			stmt.WriteString(" ")
+			stmt.WriteString(cond.operator)
+			stmt.WriteString(" ")
 		} else {
-			if final == true {
-				value.WriteString(interfaceToString(sess.stmt.where[i].value))
-				_, ok := sess.stmt.where[i].value.(string)
-				if i == 0 {
-					stmt.WriteString("(`")
-					stmt.WriteString(sess.stmt.where[i].field)
-					stmt.WriteString("`")
-					stmt.WriteString(sess.stmt.where[i].operator)
-					if ok == true {
-						stmt.WriteString("'")
-						stmt.WriteString(value.String())
-						stmt.WriteString("')")
-					} else {
-						stmt.WriteString(value.String())
-						stmt.WriteString(")")
-					}
-				} else {
-					stmt.WriteString(" ")
-					stmt.WriteString(sess.stmt.where[i].union)
-					stmt.WriteString(" (`")
-					stmt.WriteString(sess.stmt.where[i].field)
-					stmt.WriteString("`")
-					stmt.WriteString(sess.stmt.where[i].operator)
-					stmt.WriteString("'")
-					stmt.WriteString(value.String())
-					stmt.WriteString("')")
-				}
-			} else {
-				if i == 0 {
-					stmt.WriteString("(`")
-					stmt.WriteString(sess.stmt.where[i].field)
-					stmt.WriteString("`")
-					stmt.WriteString(sess.stmt.where[i].operator)
-					stmt.WriteString("?)")
-				} else {
-					stmt.WriteString(" ")
-					stmt.WriteString(sess.stmt.where[i].union)
-					stmt.WriteString(" (`")
-					stmt.WriteString(sess.stmt.where[i].field)
-					stmt.WriteString("`")
-					stmt.WriteString(sess.stmt.where[i].operator)
-					stmt.WriteString("?)")
-				}
-				sess.stmt.resultValues = append(sess.stmt.resultValues, sess.stmt.where[i].value)
-			}
+			stmt.WriteString(cond.operator)
 		}
+		//值是字段名时与另一个字段比较
+		if column, ok := cond.value.(columnName); ok == true {
+			stmt.WriteString(quoteField(string(column)))
+		} else if pattern, ok := cond.value.(likePattern); ok == true {
+			//NO_BACKSLASH_ESCAPES模式下LIKE没有默认的转义符，必须用ESCAPE声明
+			sess.writeValue(&stmt, string(pattern), final)
+			stmt.WriteString(" ESCAPE ")
+			stmt.WriteString(quoteString("\\", sess.builder.options.NoBackslashEscapes))
+		} else {
+			sess.writeValue(&stmt, cond.value, final)
+		}
+		stmt.WriteString(")")
 	}
 	return stmt.String()
 }
 
 //构建ORDER BY语句
-func (sess *Session) buildOrderBy() string {
+func (sess *Session) buildOrderBy(final bool) string {
 	if sess.err != nil {
 		return ""
 	}
-	len := len(sess.stmt.orders)
-	if len == 0 {
+	if len(sess.stmt.orders) == 0 {
 		return ""
 	}
 	var stmt bytes.Buffer
 	stmt.WriteString(" ORDER BY ")
-	for i := 0; i < len; i++ {
-		if i == 0 {
-			stmt.WriteString("`")
-			stmt.WriteString(sess.stmt.orders[i].field)
-			stmt.WriteString("` ")
-			stmt.WriteString(sess.stmt.orders[i].direction)
+	for k, v := range sess.stmt.orders {
+		if k > 0 {
+			stmt.WriteString(", ")
+		}
+		//OrderByRaw()传入的原生排序语句
+		if v.raw != nil {
+			sess.writeRaw(&stmt, v.raw, final)
+			continue
+		}
+		stmt.WriteString(quoteField(v.field))
+		stmt.WriteString(" ")
+		//查询游标之前的记录时反向排序，赋值后再恢复原来的顺序
+		if sess.stmt.keyset.before == true {
+			stmt.WriteString(reverseDirection(v.direction))
 		} else {
-			stmt.WriteString(", `")
-			stmt.WriteString(sess.stmt.orders[i].field)
-			stmt.WriteString("` ")
-			stmt.WriteString(sess.stmt.orders[i].direction)
+			stmt.WriteString(v.direction)
 		}
 	}
 
@@ -510,6 +968,10 @@ func (sess *Session) buildOffset() string {
 	}
 	var stmt bytes.Buffer
 	if sess.stmt.offset > 0 {
+		//MySQL的OFFSET必须与LIMIT同时使用，没有LIMIT时使用最大值表示不限制条数
+		if sess.stmt.limit <= 0 {
+			stmt.WriteString(" LIMIT 18446744073709551615")
+		}
 		stmt.WriteString(" OFFSET ")
 		stmt.WriteString(strconv.Itoa(sess.stmt.offset))
 	}
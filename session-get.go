@@ -1,5 +1,11 @@
 package mysqlib
 
+import (
+	"database/sql"
+	"errors"
+	"strings"
+)
+
 // GetStmt 获得已经构建的SQL语句
 func (sess *Session) GetStmt() string {
 	return sess.stmt.resultString
@@ -9,3 +15,86 @@ func (sess *Session) GetStmt() string {
 func (sess *Session) GetValues() []interface{} {
 	return sess.stmt.resultValues
 }
+
+//获得用Column()指定或由模型生成的字段名
+func (sess *Session) fieldKeys() []string {
+	keys := make([]string, len(sess.stmt.field))
+	for k, v := range sess.stmt.field {
+		keys[k] = v.key
+	}
+	return keys
+}
+
+//获得Scan时与结果集字段一一对应的模型字段名
+func (sess *Session) scanColumns(rows *sql.Rows) ([]string, error) {
+	names, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	return sess.matchColumns(names)
+}
+
+//将结果集的字段名对应到模型字段名，主表的别名或表名会被去掉，例如u.id => id
+//字段名可以是别名，例如p__avatar对应嵌套结构体p的avatar字段，但模型自身的字段名优先，例如last__login
+func (sess *Session) matchColumns(names []string) ([]string, error) {
+	qualifier := sess.tableQualifier() + "."
+	keys := sess.fieldKeys()
+	columns := make([]string, len(names))
+	for k, name := range names {
+		column := name
+		//结果集的字段名不带表的别名，与构建的字段一致时使用构建的字段，以便找到JOIN的表对应的嵌套结构体
+		if k < len(keys) {
+			if _, key := splitColumn(keys[k]); key == name {
+				column = keys[k]
+			}
+		}
+		columns[k] = strings.TrimPrefix(column, qualifier)
+		if sess.builder.options.StrictScan == true && sess.hasColumn(columns[k]) == false {
+			return nil, errors.New("结果集中的字段`" + name + "`在模型中不存在")
+		}
+	}
+	return columns, nil
+}
+
+//模型或其嵌套的结构体中是否有此字段
+func (sess *Session) hasColumn(column string) bool {
+	if _, ok := sess.modelInfo.fields[column]; ok == true {
+		return true
+	}
+	qualifier, name := splitColumn(column)
+	if qualifier == "" {
+		return false
+	}
+	nested := sess.nestedOf(qualifier)
+	if nested == nil {
+		return false
+	}
+	_, ok := nested.info.fields[name]
+	return ok
+}
+
+//根据结果集字段的前缀找到对应的嵌套结构体字段，前缀可以是嵌套结构体标记的值，也可以是JOIN的表的别名
+func (sess *Session) nestedOf(qualifier string) *nestedField {
+	for _, v := range sess.modelInfo.nested {
+		if v.prefix == qualifier {
+			return v
+		}
+	}
+	//如果前缀是JOIN的表的别名，则再用表名查找
+	for _, j := range sess.stmt.joins {
+		if j.alias != "" && j.alias == qualifier {
+			for _, v := range sess.modelInfo.nested {
+				if v.prefix == j.table {
+					return v
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// GetBatches 获得INSERT操作构建出的所有语句
+// 批量INSERT被分块成多条语句时，GetStmt()和GetValues()只返回第一条，需要用此方法获得全部语句
+func (sess *Session) GetBatches() []*Batch {
+	return sess.stmt.batches
+}
@@ -2,6 +2,8 @@ package mysqlib
 
 import (
 	"errors"
+	"reflect"
+	"strconv"
 	"strings"
 )
 
@@ -12,6 +14,60 @@ func (sess *Session) Table(tableName string) *Session {
 	return sess
 }
 
+// As 设置本次会话主表的别名，设置后可以用别名引用字段，例如Column("u.id")
+func (sess *Session) As(alias string) *Session {
+	sess.alias = alias
+	return sess
+}
+
+// Join 设置INNER JOIN子句，仅作用于SELECT操作
+// table可以是表名字符串，也可以是模型（从模型的标记中读取表名）
+// on是连接条件，例如"u.id = p.user_id"，形如"字段 运算符 字段"的条件会自动给字段加上反引号，否则直接拼接，务必注意安全
+func (sess *Session) Join(table interface{}, alias, on string) *Session {
+	return sess.joinHandle("INNER JOIN", table, alias, on)
+}
+
+// LeftJoin 设置LEFT JOIN子句，用法与Join()相同
+func (sess *Session) LeftJoin(table interface{}, alias, on string) *Session {
+	return sess.joinHandle("LEFT JOIN", table, alias, on)
+}
+
+// RightJoin 设置RIGHT JOIN子句，用法与Join()相同
+func (sess *Session) RightJoin(table interface{}, alias, on string) *Session {
+	return sess.joinHandle("RIGHT JOIN", table, alias, on)
+}
+
+func (sess *Session) joinHandle(kind string, table interface{}, alias, on string) *Session {
+	if sess.stmt.action != "SELECT" {
+		sess.err = errors.New("JOIN只能用于SELECT操作")
+		return sess
+	}
+	var j join
+	j.kind = kind
+	j.alias = alias
+	j.on = on
+	if tableName, ok := table.(string); ok == true {
+		j.table = tableName
+	} else {
+		//从模型的标记中读取表名
+		rType := reflect.TypeOf(table)
+		for rType != nil && (rType.Kind() == reflect.Ptr || rType.Kind() == reflect.Slice) {
+			rType = rType.Elem()
+		}
+		if rType == nil || rType.Kind() != reflect.Struct {
+			sess.err = errors.New("JOIN的表必须是表名或者模型")
+			return sess
+		}
+		j.table = sess.builder.getModelInfo(rType).tableName
+	}
+	if j.table == "" {
+		sess.err = errors.New("JOIN没有定义表名")
+		return sess
+	}
+	sess.stmt.joins = append(sess.stmt.joins, &j)
+	return sess
+}
+
 // Column 要影响的字段，作用于以下操作：
 // INSERT：只插入哪些字段
 // UPDATE：只更新哪些字段
@@ -28,9 +84,27 @@ func (sess *Session) Column(fields ...string) *Session {
 	return sess
 }
 
+// ColumnRaw 添加原生语句作为SELECT的字段，alias是字段的别名
+// 语句中的?占位符依次对应args，不要把外部输入直接拼接到语句中
+func (sess *Session) ColumnRaw(stmt, alias string, args ...interface{}) *Session {
+	if sess.stmt.action != "SELECT" {
+		sess.err = errors.New("`ColumnRaw()`只能用于`SELECT`操作")
+		return sess
+	}
+	if alias == "" {
+		sess.err = errors.New("原生字段必须指定别名")
+		return sess
+	}
+	var field keyInterface
+	field.key = alias
+	field.value = &rawExpr{stmt: stmt, args: args}
+	sess.stmt.field = append(sess.stmt.field, &field)
+	return sess
+}
+
 // AddValue 用于在Insert和Update操作时，添加模型中没有定义的字段及其值
 func (sess *Session) AddValue(fieldName string, value ...interface{}) *Session {
-	if sess.stmt.action != "UPDATE" && sess.stmt.action != "INSERT" {
+	if sess.stmt.action != "UPDATE" && sess.stmt.action != "INSERT" && sess.stmt.action != "REPLACE" {
 		return sess
 	}
 	var field keyInterface
@@ -42,76 +116,234 @@ func (sess *Session) AddValue(fieldName string, value ...interface{}) *Session {
 	return sess
 }
 
+// SetRaw 用于在Insert和Update操作时，将字段的值设置为原生语句，例如SetRaw("updated_at", "FROM_UNIXTIME(?)", ts)
+// 语句中的?占位符依次对应args，不要把外部输入直接拼接到语句中
+func (sess *Session) SetRaw(fieldName, stmt string, args ...interface{}) *Session {
+	return sess.AddValue(fieldName, &rawExpr{stmt: stmt, args: args})
+}
+
+// Incr 用于Update操作，将字段的值增加n，例如Incr("views", 1)构建`views`=`views`+?
+func (sess *Session) Incr(fieldName string, n interface{}) *Session {
+	return sess.setHandle("Incr", fieldName, &rawExpr{stmt: quoteField(fieldName) + "+?", args: []interface{}{n}})
+}
+
+// Decr 用于Update操作，将字段的值减少n
+func (sess *Session) Decr(fieldName string, n interface{}) *Session {
+	return sess.setHandle("Decr", fieldName, &rawExpr{stmt: quoteField(fieldName) + "-?", args: []interface{}{n}})
+}
+
+// SetExpr 用于Update操作，将字段的值设置为表达式，例如SetExpr("updated_at", "NOW()")
+// 表达式会直接拼接，不能包含?占位符，需要参数时使用SetRaw()
+func (sess *Session) SetExpr(fieldName, expr string) *Session {
+	return sess.setHandle("SetExpr", fieldName, &rawExpr{stmt: expr})
+}
+
+// SetColumn 用于Update操作，将字段的值设置为另一个字段的值，例如SetColumn("nickname", "username")
+func (sess *Session) SetColumn(fieldName, srcField string) *Session {
+	if err := checkField(srcField); err != nil {
+		sess.err = err
+		return sess
+	}
+	return sess.setHandle("SetColumn", fieldName, &rawExpr{stmt: quoteField(srcField)})
+}
+
+//添加UPDATE的SET表达式，method是调用的方法名，用于错误消息
+func (sess *Session) setHandle(method, fieldName string, raw *rawExpr) *Session {
+	if sess.stmt.action != "UPDATE" {
+		sess.err = errors.New("`" + method + "()`只能用于`UPDATE`操作")
+		return sess
+	}
+	return sess.AddValue(fieldName, raw)
+}
+
 // Where 设置AND WHERE条件，作用跟AndWhere()一样
 func (sess *Session) Where(field, operator string, value interface{}) *Session {
-	return sess.whereHandle("AND", field, operator, value)
+	sess.stmt.where.Where(field, operator, value)
+	return sess
 }
 
 // AndWhere 设置AND WHERE条件
 func (sess *Session) AndWhere(field, operator string, value interface{}) *Session {
-	return sess.whereHandle("AND", field, operator, value)
+	sess.stmt.where.AndWhere(field, operator, value)
+	return sess
 }
 
 // OrWhere 设置OR WHERE
 func (sess *Session) OrWhere(field, operator string, value interface{}) *Session {
-	return sess.whereHandle("OR", field, operator, value)
+	sess.stmt.where.OrWhere(field, operator, value)
+	return sess
 }
 
 // WhereRaw 传入原生WHERE语句
-// 传入的字符串会直接拼接，务必注意安全
-func (sess *Session) WhereRaw(stmt string) *Session {
-	return sess.whereHandle("AND", stmt, "[!RAW!]", nil)
+// 语句中的?占位符依次对应args，不要把外部输入直接拼接到语句中
+func (sess *Session) WhereRaw(stmt string, args ...interface{}) *Session {
+	sess.stmt.where.WhereRaw(stmt, args...)
+	return sess
 }
 
 // AndWhereRaw 传入原生AND WHERE语句
-// 传入的字符串会直接拼接，务必注意安全
-func (sess *Session) AndWhereRaw(stmt string) *Session {
-	return sess.whereHandle("AND", stmt, "[!RAW!]", nil)
+// 语句中的?占位符依次对应args，不要把外部输入直接拼接到语句中
+func (sess *Session) AndWhereRaw(stmt string, args ...interface{}) *Session {
+	sess.stmt.where.AndWhereRaw(stmt, args...)
+	return sess
 }
 
 // OrWhereRaw 传入原生OR WHERE语句
-// 传入的字符串会直接拼接，务必注意安全
-func (sess *Session) OrWhereRaw(stmt string) *Session {
-	return sess.whereHandle("OR", stmt, "[!RAW!]", nil)
+// 语句中的?占位符依次对应args，不要把外部输入直接拼接到语句中
+func (sess *Session) OrWhereRaw(stmt string, args ...interface{}) *Session {
+	sess.stmt.where.OrWhereRaw(stmt, args...)
+	return sess
 }
 
 // WhereIn 传入WHERE IN语句，作用和AndWhereIn()一样
 func (sess *Session) WhereIn(field string, value interface{}) *Session {
-	return sess.whereHandle("AND", field, "IN", value)
+	sess.stmt.where.WhereIn(field, value)
+	return sess
 }
 
 // AndWhereIn 传入AND IN语句
 func (sess *Session) AndWhereIn(field string, value interface{}) *Session {
-	return sess.whereHandle("AND", field, "IN", value)
+	sess.stmt.where.AndWhereIn(field, value)
+	return sess
 }
 
 // OrWhereIn 传入OR IN语句
 func (sess *Session) OrWhereIn(field string, value interface{}) *Session {
-	return sess.whereHandle("OR", field, "IN", value)
+	sess.stmt.where.OrWhereIn(field, value)
+	return sess
 }
 
 // WhereNotIn 传入AND NOT IN语句，作用和AndWhereNotIn()一样
 func (sess *Session) WhereNotIn(field string, value interface{}) *Session {
-	return sess.whereHandle("AND", field, "NOT IN", value)
+	sess.stmt.where.WhereNotIn(field, value)
+	return sess
 }
 
 // AndWhereNotIn 传入AND NOT IN语句
 func (sess *Session) AndWhereNotIn(field string, value interface{}) *Session {
-	return sess.whereHandle("AND", field, "NOT IN", value)
+	sess.stmt.where.AndWhereNotIn(field, value)
+	return sess
 }
 
 // OrWhereNotIn 传入OR NOT IN语句
 func (sess *Session) OrWhereNotIn(field string, value interface{}) *Session {
-	return sess.whereHandle("OR", field, "NOT IN", value)
+	sess.stmt.where.OrWhereNotIn(field, value)
+	return sess
+}
+
+// WhereGroup 设置AND条件组，作用和AndWhereGroup()一样，回调函数中设置的条件会用括号包裹
+// 例如WhereGroup(func(c *Cond) { c.Where("a", "=", 1).OrWhere("b", "=", 2) }).Where("c", "=", 3)
+// 渲染为((`a`=?) OR (`b`=?)) AND (`c`=?)
+func (sess *Session) WhereGroup(fn func(*Cond)) *Session {
+	sess.stmt.where.WhereGroup(fn)
+	return sess
 }
 
-func (sess *Session) whereHandle(union, field, operator string, value interface{}) *Session {
-	var cond whereCond
-	cond.union = union
-	cond.field = field
-	cond.operator = operator
-	cond.value = value
-	sess.stmt.where = append(sess.stmt.where, &cond)
+// AndWhereGroup 设置AND条件组
+func (sess *Session) AndWhereGroup(fn func(*Cond)) *Session {
+	sess.stmt.where.AndWhereGroup(fn)
+	return sess
+}
+
+// OrWhereGroup 设置OR条件组
+func (sess *Session) OrWhereGroup(fn func(*Cond)) *Session {
+	sess.stmt.where.OrWhereGroup(fn)
+	return sess
+}
+
+// WhereCond 设置AND条件组，作用和AndWhereCond()一样，条件组可以用Where()、And()、Or()、Not()组合
+// 例如WhereCond(Or(Where("a", "=", 1), Not(Where("b", "=", 2))))
+func (sess *Session) WhereCond(cond *Cond) *Session {
+	sess.stmt.where.WhereCond(cond)
+	return sess
+}
+
+// AndWhereCond 设置AND条件组
+func (sess *Session) AndWhereCond(cond *Cond) *Session {
+	sess.stmt.where.AndWhereCond(cond)
+	return sess
+}
+
+// OrWhereCond 设置OR条件组
+func (sess *Session) OrWhereCond(cond *Cond) *Session {
+	sess.stmt.where.OrWhereCond(cond)
+	return sess
+}
+
+// GroupBy 设置GROUP BY分组字段
+func (sess *Session) GroupBy(fields ...string) *Session {
+	if sess.stmt.action != "SELECT" {
+		return sess
+	}
+	sess.stmt.groups = append(sess.stmt.groups, fields...)
+	return sess
+}
+
+// Having 设置AND HAVING条件，作用跟AndHaving()一样
+// field可以是聚合函数的别名，例如ColumnCount("id", "total").Having("total", ">", 10)
+func (sess *Session) Having(field, operator string, value interface{}) *Session {
+	return sess.havingHandle("AND", field, operator, value)
+}
+
+// AndHaving 设置AND HAVING条件
+func (sess *Session) AndHaving(field, operator string, value interface{}) *Session {
+	return sess.havingHandle("AND", field, operator, value)
+}
+
+// OrHaving 设置OR HAVING条件
+func (sess *Session) OrHaving(field, operator string, value interface{}) *Session {
+	return sess.havingHandle("OR", field, operator, value)
+}
+
+func (sess *Session) havingHandle(union, field, operator string, value interface{}) *Session {
+	if sess.stmt.action != "SELECT" {
+		return sess
+	}
+	sess.stmt.having = append(sess.stmt.having, newWhereCond(union, field, operator, value))
+	return sess
+}
+
+// ColumnCount 要返回的COUNT()聚合字段，field为*时渲染为COUNT(*)，alias是结果集中的字段名，Scan时赋值给模型中同名的字段
+func (sess *Session) ColumnCount(field, alias string) *Session {
+	return sess.aggregateHandle("COUNT", field, alias)
+}
+
+// ColumnSum 要返回的SUM()聚合字段，用法与ColumnCount()相同
+func (sess *Session) ColumnSum(field, alias string) *Session {
+	return sess.aggregateHandle("SUM", field, alias)
+}
+
+// ColumnAvg 要返回的AVG()聚合字段，用法与ColumnCount()相同
+func (sess *Session) ColumnAvg(field, alias string) *Session {
+	return sess.aggregateHandle("AVG", field, alias)
+}
+
+// ColumnMin 要返回的MIN()聚合字段，用法与ColumnCount()相同
+func (sess *Session) ColumnMin(field, alias string) *Session {
+	return sess.aggregateHandle("MIN", field, alias)
+}
+
+// ColumnMax 要返回的MAX()聚合字段，用法与ColumnCount()相同
+func (sess *Session) ColumnMax(field, alias string) *Session {
+	return sess.aggregateHandle("MAX", field, alias)
+}
+
+func (sess *Session) aggregateHandle(function, field, alias string) *Session {
+	if sess.stmt.action != "SELECT" {
+		return sess
+	}
+	if alias == "" {
+		sess.err = errors.New("聚合字段必须指定别名")
+		return sess
+	}
+	if err := checkField(field); err != nil {
+		sess.err = err
+		return sess
+	}
+	var column keyInterface
+	column.key = alias
+	column.value = function + "(" + quoteField(field) + ")"
+	sess.stmt.field = append(sess.stmt.field, &column)
 	return sess
 }
 
@@ -129,6 +361,15 @@ func (sess *Session) OrderBy(field, direction string) *Session {
 	return sess
 }
 
+// OrderByRaw 添加原生排序语句，例如OrderByRaw("FIELD(`status`, ?, ?)", 2, 1)
+// 语句中的?占位符依次对应args，不要把外部输入直接拼接到语句中
+func (sess *Session) OrderByRaw(stmt string, args ...interface{}) *Session {
+	var order orderBy
+	order.raw = &rawExpr{stmt: stmt, args: args}
+	sess.stmt.orders = append(sess.stmt.orders, &order)
+	return sess
+}
+
 //Limit 限制返回记录条数
 func (sess *Session) Limit(value int) *Session {
 	sess.stmt.limit = value
@@ -143,3 +384,262 @@ func (sess *Session) Offset(value int) *Session {
 	sess.stmt.offset = value
 	return sess
 }
+
+// BatchSize 设置批量INSERT时每条语句最多包含的记录数，超出时会分块成多条语句
+// 占位符模式下每条语句的?占位符数量不会超过65535个，记录数超出此限制时也会分块
+func (sess *Session) BatchSize(rows int) *Session {
+	if sess.stmt.action != "INSERT" && sess.stmt.action != "REPLACE" {
+		return sess
+	}
+	sess.stmt.batchSize = rows
+	return sess
+}
+
+// OnDuplicateKeyUpdate 设置INSERT遇到重复键时要更新的字段，更新的值取自本次要插入的值
+// 默认渲染为`field`=VALUES(`field`)，如果用RowAlias()设置了行别名，则渲染为`field`=别名.`field`
+func (sess *Session) OnDuplicateKeyUpdate(fields ...string) *Session {
+	for _, v := range fields {
+		sess.duplicateHandle(v, "VALUES", nil)
+	}
+	return sess
+}
+
+// OnDuplicateKeySet 设置INSERT遇到重复键时将字段更新为指定的值
+func (sess *Session) OnDuplicateKeySet(field string, value interface{}) *Session {
+	return sess.duplicateHandle(field, "SET", value)
+}
+
+// OnDuplicateKeyExpr 设置INSERT遇到重复键时将字段更新为表达式的结果，例如NOW()
+// 传入的表达式会直接拼接，务必注意安全
+func (sess *Session) OnDuplicateKeyExpr(field, expr string) *Session {
+	return sess.duplicateHandle(field, "EXPR", expr)
+}
+
+// OnDuplicateKeyIncr 设置INSERT遇到重复键时将字段的值累加，value为负数时即为递减
+func (sess *Session) OnDuplicateKeyIncr(field string, value interface{}) *Session {
+	return sess.duplicateHandle(field, "INCR", value)
+}
+
+// RowAlias 设置INSERT的行别名，渲染为VALUES (...) AS alias，需要MySQL 8.0.19及以上版本
+// 设置后OnDuplicateKeyUpdate()将使用别名引用要插入的值，代替已被弃用的VALUES()函数
+func (sess *Session) RowAlias(alias string) *Session {
+	if sess.stmt.action != "INSERT" {
+		return sess
+	}
+	sess.stmt.rowAlias = alias
+	return sess
+}
+
+func (sess *Session) duplicateHandle(field, mode string, value interface{}) *Session {
+	if sess.stmt.action != "INSERT" {
+		return sess
+	}
+	var set duplicateSet
+	set.field = field
+	set.mode = mode
+	set.value = value
+	sess.stmt.duplicate = append(sess.stmt.duplicate, &set)
+	return sess
+}
+
+// Ignore 设置INSERT操作忽略重复键等错误，渲染为INSERT IGNORE INTO
+func (sess *Session) Ignore() *Session {
+	if sess.stmt.action != "INSERT" {
+		return sess
+	}
+	sess.stmt.ignore = true
+	return sess
+}
+
+// With 添加公用表表达式（MySQL 8.0+），渲染为WITH `name` (columns) AS (query)，本会话可以用Table(name)或JOIN引用它
+// 可用于SELECT/UPDATE/DELETE，query会话不需要单独执行Build()，其参数值会按顺序汇总到本会话中
+func (sess *Session) With(name string, query *Session, columns ...string) *Session {
+	return sess.withHandle(false, name, query, columns)
+}
+
+// WithRecursive 添加递归的公用表表达式，渲染为WITH RECURSIVE ...，用法与With()相同
+// query会话通常是初始记录的查询使用UnionAll()合并引用name的递归查询
+func (sess *Session) WithRecursive(name string, query *Session, columns ...string) *Session {
+	return sess.withHandle(true, name, query, columns)
+}
+
+func (sess *Session) withHandle(recursive bool, name string, query *Session, columns []string) *Session {
+	if sess.stmt.action != "SELECT" && sess.stmt.action != "UPDATE" && sess.stmt.action != "DELETE" {
+		sess.err = errors.New("只有SELECT/UPDATE/DELETE会话才能使用WITH")
+		return sess
+	}
+	if query == nil || query.stmt.action != "SELECT" {
+		sess.err = errors.New("WITH的参数必须是SELECT会话")
+		return sess
+	}
+	if query == sess {
+		sess.err = errors.New("WITH不能引用会话本身")
+		return sess
+	}
+	//WITH RECURSIVE作用于整个WITH子句
+	if recursive == true {
+		sess.stmt.recursive = true
+	}
+	sess.stmt.ctes = append(sess.stmt.ctes, &cte{name: name, columns: columns, sess: query})
+	return sess
+}
+
+// From 设置SELECT会话从另一个SELECT会话的结果中查询，渲染为FROM (SELECT ...) AS `alias`
+// 派生表必须指定别名，引用派生表的字段时使用别名，sub会话不需要单独执行Build()，其参数值会按顺序汇总到本会话中
+func (sess *Session) From(sub *Session, alias string) *Session {
+	if sess.stmt.action != "SELECT" {
+		sess.err = errors.New("只有SELECT会话才能使用派生表")
+		return sess
+	}
+	if sub == nil || sub.stmt.action != "SELECT" {
+		sess.err = errors.New("派生表必须是SELECT会话")
+		return sess
+	}
+	if alias == "" {
+		sess.err = errors.New("派生表必须指定别名")
+		return sess
+	}
+	sess.stmt.from = sub
+	//派生表的别名作为本会话的表名
+	sess.tableName = alias
+	sess.alias = ""
+	return sess
+}
+
+// Union 使用UNION合并其它SELECT会话的结果（去重），各会话的字段数量和顺序必须一致
+// 本会话的ORDER BY/LIMIT/OFFSET作用于合并后的结果，要合并的会话不需要单独执行Build()，其参数值会按顺序汇总到本会话中
+func (sess *Session) Union(others ...*Session) *Session {
+	return sess.unionHandle(false, others)
+}
+
+// UnionAll 使用UNION ALL合并其它SELECT会话的结果（不去重），用法与Union()相同
+func (sess *Session) UnionAll(others ...*Session) *Session {
+	return sess.unionHandle(true, others)
+}
+
+func (sess *Session) unionHandle(all bool, others []*Session) *Session {
+	if sess.stmt.action != "SELECT" {
+		sess.err = errors.New("只有SELECT会话才能使用UNION")
+		return sess
+	}
+	for _, v := range others {
+		if v == nil || v.stmt.action != "SELECT" {
+			sess.err = errors.New("UNION的参数必须是SELECT会话")
+			return sess
+		}
+		if v == sess {
+			sess.err = errors.New("UNION不能合并会话本身")
+			return sess
+		}
+		sess.stmt.unions = append(sess.stmt.unions, &union{all: all, sess: v})
+	}
+	return sess
+}
+
+// ForUpdate 设置SELECT的锁定读为FOR UPDATE，在事务中对查询到的记录加排它锁
+// 使用JOIN时可以传入表名或别名，渲染为FOR UPDATE OF t1, t2，只锁定这些表的记录
+func (sess *Session) ForUpdate(tables ...string) *Session {
+	return sess.lockHandle("FOR UPDATE", tables)
+}
+
+// ForShare 设置SELECT的锁定读为FOR SHARE（MySQL 8.0+），对查询到的记录加共享锁，用法与ForUpdate()相同
+func (sess *Session) ForShare(tables ...string) *Session {
+	return sess.lockHandle("FOR SHARE", tables)
+}
+
+// LockInShareMode 设置SELECT的锁定读为LOCK IN SHARE MODE，作用与ForShare()一样，兼容MySQL 5.7
+// 不能与NoWait()、SkipLocked()同时使用
+func (sess *Session) LockInShareMode() *Session {
+	return sess.lockHandle("LOCK IN SHARE MODE", nil)
+}
+
+// NoWait 设置锁定读遇到已被锁定的记录时立即返回错误，而不是等待（MySQL 8.0+）
+func (sess *Session) NoWait() *Session {
+	sess.stmt.lock.wait = "NOWAIT"
+	return sess
+}
+
+// SkipLocked 设置锁定读跳过已被锁定的记录（MySQL 8.0+），适用于多个消费者从同一个表中领取任务的场景
+func (sess *Session) SkipLocked() *Session {
+	sess.stmt.lock.wait = "SKIP LOCKED"
+	return sess
+}
+
+func (sess *Session) lockHandle(mode string, tables []string) *Session {
+	if sess.stmt.action != "SELECT" {
+		sess.err = errors.New("只有SELECT会话才能使用锁定读")
+		return sess
+	}
+	sess.stmt.lock.mode = mode
+	sess.stmt.lock.tables = tables
+	return sess
+}
+
+// UseIndex 设置主表的索引提示USE INDEX，建议优化器只使用指定的索引
+// 可用于SELECT/UPDATE/DELETE，DELETE使用索引提示时不能同时使用OrderBy()和Limit()
+func (sess *Session) UseIndex(names ...string) *Session {
+	return sess.indexHintHandle("USE", names)
+}
+
+// ForceIndex 设置主表的索引提示FORCE INDEX，强制优化器使用指定的索引，用法与UseIndex()相同
+func (sess *Session) ForceIndex(names ...string) *Session {
+	return sess.indexHintHandle("FORCE", names)
+}
+
+// IgnoreIndex 设置主表的索引提示IGNORE INDEX，禁止优化器使用指定的索引，用法与UseIndex()相同
+func (sess *Session) IgnoreIndex(names ...string) *Session {
+	return sess.indexHintHandle("IGNORE", names)
+}
+
+func (sess *Session) indexHintHandle(kind string, names []string) *Session {
+	if sess.stmt.action != "SELECT" && sess.stmt.action != "UPDATE" && sess.stmt.action != "DELETE" {
+		sess.err = errors.New("只有SELECT/UPDATE/DELETE会话才能使用索引提示")
+		return sess
+	}
+	if len(names) == 0 {
+		sess.err = errors.New("索引提示必须指定索引名")
+		return sess
+	}
+	sess.stmt.indexHints = append(sess.stmt.indexHints, &indexHint{kind: kind, names: names})
+	return sess
+}
+
+// Hint 添加优化器提示，渲染为SELECT /*+ hint1 hint2 */ ...，例如Hint("NO_INDEX_MERGE(user)")
+// 提示会直接拼接，不要把外部输入作为提示
+func (sess *Session) Hint(hints ...string) *Session {
+	sess.stmt.hints = append(sess.stmt.hints, hints...)
+	return sess
+}
+
+// MaxExecutionTime 添加优化器提示MAX_EXECUTION_TIME，限制SELECT语句的执行时间，单位是毫秒
+func (sess *Session) MaxExecutionTime(ms int) *Session {
+	if sess.stmt.action != "SELECT" {
+		sess.err = errors.New("`MaxExecutionTime()`只能用于`SELECT`操作")
+		return sess
+	}
+	return sess.Hint("MAX_EXECUTION_TIME(" + strconv.Itoa(ms) + ")")
+}
+
+// SetVar 添加优化器提示SET_VAR，只在执行本语句时修改系统变量，例如SetVar("sort_buffer_size", "16M")
+func (sess *Session) SetVar(name, value string) *Session {
+	if hintPattern.MatchString(name) == false || hintPattern.MatchString(value) == false {
+		sess.err = errors.New("SET_VAR的变量名和值只能包含字母、数字、下划线和小数点")
+		return sess
+	}
+	return sess.Hint("SET_VAR(" + name + "=" + value + ")")
+}
+
+// FromSelect 设置INSERT/REPLACE操作插入的是SELECT会话查询出的记录，渲染为INSERT INTO t (...) SELECT ...
+// 要插入的字段仍然由Column()或模型决定，其数量和顺序必须与SELECT会话返回的字段一致
+// SELECT会话不需要单独执行Build()，其参数值会按顺序汇总到本会话中
+func (sess *Session) FromSelect(selectSess *Session) *Session {
+	if sess.stmt.action != "INSERT" && sess.stmt.action != "REPLACE" {
+		return sess
+	}
+	if selectSess == nil || selectSess.stmt.action != "SELECT" {
+		sess.err = errors.New("FromSelect()的参数必须是SELECT会话")
+		return sess
+	}
+	sess.stmt.fromSelect = selectSess
+	return sess
+}
@@ -0,0 +1,219 @@
+package mysqlib
+
+//会话的WHERE条件方法与Cond的同名方法作用一样，都委托给会话的根条件组sess.stmt.where
+
+// WhereBetween 设置BETWEEN条件，匹配start和end之间（含两端）的值，作用和AndWhereBetween()一样
+func (sess *Session) WhereBetween(field string, start, end interface{}) *Session {
+	sess.stmt.where.WhereBetween(field, start, end)
+	return sess
+}
+
+// AndWhereBetween 设置AND BETWEEN条件
+func (sess *Session) AndWhereBetween(field string, start, end interface{}) *Session {
+	sess.stmt.where.AndWhereBetween(field, start, end)
+	return sess
+}
+
+// OrWhereBetween 设置OR BETWEEN条件
+func (sess *Session) OrWhereBetween(field string, start, end interface{}) *Session {
+	sess.stmt.where.OrWhereBetween(field, start, end)
+	return sess
+}
+
+// WhereNotBetween 设置NOT BETWEEN条件，作用和AndWhereNotBetween()一样
+func (sess *Session) WhereNotBetween(field string, start, end interface{}) *Session {
+	sess.stmt.where.WhereNotBetween(field, start, end)
+	return sess
+}
+
+// AndWhereNotBetween 设置AND NOT BETWEEN条件
+func (sess *Session) AndWhereNotBetween(field string, start, end interface{}) *Session {
+	sess.stmt.where.AndWhereNotBetween(field, start, end)
+	return sess
+}
+
+// OrWhereNotBetween 设置OR NOT BETWEEN条件
+func (sess *Session) OrWhereNotBetween(field string, start, end interface{}) *Session {
+	sess.stmt.where.OrWhereNotBetween(field, start, end)
+	return sess
+}
+
+// WhereLike 设置LIKE条件，pattern中的%和_作为通配符使用，不会转义，作用和AndWhereLike()一样
+func (sess *Session) WhereLike(field, pattern string) *Session {
+	sess.stmt.where.WhereLike(field, pattern)
+	return sess
+}
+
+// AndWhereLike 设置AND LIKE条件
+func (sess *Session) AndWhereLike(field, pattern string) *Session {
+	sess.stmt.where.AndWhereLike(field, pattern)
+	return sess
+}
+
+// OrWhereLike 设置OR LIKE条件
+func (sess *Session) OrWhereLike(field, pattern string) *Session {
+	sess.stmt.where.OrWhereLike(field, pattern)
+	return sess
+}
+
+// WhereStartsWith 设置LIKE条件，匹配以value开头的值，value中的%和_会被转义，作用和AndWhereStartsWith()一样
+func (sess *Session) WhereStartsWith(field, value string) *Session {
+	sess.stmt.where.WhereStartsWith(field, value)
+	return sess
+}
+
+// AndWhereStartsWith 设置AND LIKE前缀匹配条件
+func (sess *Session) AndWhereStartsWith(field, value string) *Session {
+	sess.stmt.where.AndWhereStartsWith(field, value)
+	return sess
+}
+
+// OrWhereStartsWith 设置OR LIKE前缀匹配条件
+func (sess *Session) OrWhereStartsWith(field, value string) *Session {
+	sess.stmt.where.OrWhereStartsWith(field, value)
+	return sess
+}
+
+// WhereEndsWith 设置LIKE条件，匹配以value结尾的值，value中的%和_会被转义，作用和AndWhereEndsWith()一样
+func (sess *Session) WhereEndsWith(field, value string) *Session {
+	sess.stmt.where.WhereEndsWith(field, value)
+	return sess
+}
+
+// AndWhereEndsWith 设置AND LIKE后缀匹配条件
+func (sess *Session) AndWhereEndsWith(field, value string) *Session {
+	sess.stmt.where.AndWhereEndsWith(field, value)
+	return sess
+}
+
+// OrWhereEndsWith 设置OR LIKE后缀匹配条件
+func (sess *Session) OrWhereEndsWith(field, value string) *Session {
+	sess.stmt.where.OrWhereEndsWith(field, value)
+	return sess
+}
+
+// WhereContains 设置LIKE条件，匹配包含value的值，value中的%和_会被转义，作用和AndWhereContains()一样
+func (sess *Session) WhereContains(field, value string) *Session {
+	sess.stmt.where.WhereContains(field, value)
+	return sess
+}
+
+// AndWhereContains 设置AND LIKE包含匹配条件
+func (sess *Session) AndWhereContains(field, value string) *Session {
+	sess.stmt.where.AndWhereContains(field, value)
+	return sess
+}
+
+// OrWhereContains 设置OR LIKE包含匹配条件
+func (sess *Session) OrWhereContains(field, value string) *Session {
+	sess.stmt.where.OrWhereContains(field, value)
+	return sess
+}
+
+// WhereNull 设置IS NULL条件，作用和AndWhereNull()一样
+func (sess *Session) WhereNull(field string) *Session {
+	sess.stmt.where.WhereNull(field)
+	return sess
+}
+
+// AndWhereNull 设置AND IS NULL条件
+func (sess *Session) AndWhereNull(field string) *Session {
+	sess.stmt.where.AndWhereNull(field)
+	return sess
+}
+
+// OrWhereNull 设置OR IS NULL条件
+func (sess *Session) OrWhereNull(field string) *Session {
+	sess.stmt.where.OrWhereNull(field)
+	return sess
+}
+
+// WhereNotNull 设置IS NOT NULL条件，作用和AndWhereNotNull()一样
+func (sess *Session) WhereNotNull(field string) *Session {
+	sess.stmt.where.WhereNotNull(field)
+	return sess
+}
+
+// AndWhereNotNull 设置AND IS NOT NULL条件
+func (sess *Session) AndWhereNotNull(field string) *Session {
+	sess.stmt.where.AndWhereNotNull(field)
+	return sess
+}
+
+// OrWhereNotNull 设置OR IS NOT NULL条件
+func (sess *Session) OrWhereNotNull(field string) *Session {
+	sess.stmt.where.OrWhereNotNull(field)
+	return sess
+}
+
+// WhereColumn 设置两个字段比较的条件，例如WhereColumn("updated_at", ">", "created_at")，作用和AndWhereColumn()一样
+func (sess *Session) WhereColumn(field, operator, column string) *Session {
+	sess.stmt.where.WhereColumn(field, operator, column)
+	return sess
+}
+
+// AndWhereColumn 设置AND 字段比较条件
+func (sess *Session) AndWhereColumn(field, operator, column string) *Session {
+	sess.stmt.where.AndWhereColumn(field, operator, column)
+	return sess
+}
+
+// OrWhereColumn 设置OR 字段比较条件
+func (sess *Session) OrWhereColumn(field, operator, column string) *Session {
+	sess.stmt.where.OrWhereColumn(field, operator, column)
+	return sess
+}
+
+// WhereRegexp 设置REGEXP正则匹配条件，作用和AndWhereRegexp()一样
+func (sess *Session) WhereRegexp(field, pattern string) *Session {
+	sess.stmt.where.WhereRegexp(field, pattern)
+	return sess
+}
+
+// AndWhereRegexp 设置AND REGEXP条件
+func (sess *Session) AndWhereRegexp(field, pattern string) *Session {
+	sess.stmt.where.AndWhereRegexp(field, pattern)
+	return sess
+}
+
+// OrWhereRegexp 设置OR REGEXP条件
+func (sess *Session) OrWhereRegexp(field, pattern string) *Session {
+	sess.stmt.where.OrWhereRegexp(field, pattern)
+	return sess
+}
+
+// WhereExists 设置EXISTS子查询条件，sub必须是SELECT会话，作用和AndWhereExists()一样
+func (sess *Session) WhereExists(sub *Session) *Session {
+	sess.stmt.where.WhereExists(sub)
+	return sess
+}
+
+// AndWhereExists 设置AND EXISTS子查询条件
+func (sess *Session) AndWhereExists(sub *Session) *Session {
+	sess.stmt.where.AndWhereExists(sub)
+	return sess
+}
+
+// OrWhereExists 设置OR EXISTS子查询条件
+func (sess *Session) OrWhereExists(sub *Session) *Session {
+	sess.stmt.where.OrWhereExists(sub)
+	return sess
+}
+
+// WhereNotExists 设置NOT EXISTS子查询条件，sub必须是SELECT会话，作用和AndWhereNotExists()一样
+func (sess *Session) WhereNotExists(sub *Session) *Session {
+	sess.stmt.where.WhereNotExists(sub)
+	return sess
+}
+
+// AndWhereNotExists 设置AND NOT EXISTS子查询条件
+func (sess *Session) AndWhereNotExists(sub *Session) *Session {
+	sess.stmt.where.AndWhereNotExists(sub)
+	return sess
+}
+
+// OrWhereNotExists 设置OR NOT EXISTS子查询条件
+func (sess *Session) OrWhereNotExists(sub *Session) *Session {
+	sess.stmt.where.OrWhereNotExists(sub)
+	return sess
+}
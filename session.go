@@ -14,22 +14,52 @@ type Session struct {
 		rValue  reflect.Value //模型实例的reflectValue
 		rType   reflect.Type  //模型实例的reflectType
 	}
-	tableName string //临时作用于本次会话的表名
+	tableName string                 //临时作用于本次会话的表名
+	alias     string                 //本次会话主表的别名
+	snapshot  map[string]interface{} //UPDATE会话构建时取得的模型快照，用于找出被修改的字段
 	//sql语句的结构
 	stmt struct {
 		action       string          //行为
 		field        []*keyInterface //INSERT/UPDATE要从模型中取值的字段
 		addValue     []*keyInterface //INSERT/UPDATE要写值到模型外的字段及其值
-		where        []*whereCond    //where条件
+		joins        []*join         //JOIN子句
+		where        Cond            //where条件，会话的WHERE条件方法都委托给它
+		groups       []string        //GROUP BY分组字段
+		having       []*whereCond    //HAVING条件
 		orders       []*orderBy
 		limit        int
 		offset       int
-		resultString string        //最终生成的sql语句字符串
-		resultValues []interface{} //最终汇总的参数值
+		batchSize    int             //批量INSERT时每条语句最多包含的记录数
+		batches      []*Batch        //INSERT操作构建出的语句
+		duplicate    []*duplicateSet //INSERT的ON DUPLICATE KEY UPDATE子句
+		rowAlias     string          //INSERT的行别名（MySQL 8.0.19+的VALUES (...) AS new语法）
+		ignore       bool            //INSERT IGNORE
+		fromSelect   *Session        //INSERT ... SELECT的SELECT会话
+		unions       []*union        //UNION合并的SELECT会话
+		lock         lockClause      //SELECT的锁定读子句
+		indexHints   []*indexHint    //主表的索引提示
+		hints        []string        //优化器提示
+		ctes         []*cte          //WITH子句的公用表表达式
+		from         *Session        //作为派生表的SELECT会话
+		keyset       keyset          //游标分页
+		recursive    bool            //是否是WITH RECURSIVE
+		resultString string          //最终生成的sql语句字符串
+		resultValues []interface{}   //最终汇总的参数值
+		buildErr     error           //本次构建过程中产生的错误，例如无法编码的参数值，每次构建时重置
+		building     bool            //是否正在构建，用于发现会话之间的循环引用
 	}
 	err error //错误
 }
 
+// Batch INSERT操作构建出的一条语句
+// 批量INSERT的记录数超出BatchSize()或占位符数量上限时，会分块成多条语句
+type Batch struct {
+	Stmt   string          //SQL语句
+	Values []interface{}   //SQL语句中?占位符对应的参数值
+	sess   *Session        //所属的会话
+	rows   []reflect.Value //本条语句插入的记录
+}
+
 type keyInterface struct {
 	key   string
 	value interface{}
@@ -41,26 +71,106 @@ type modelInfo struct {
 	tableName  string                 //sql表名
 	fieldCount int                    //sql字段数（仅含标记信息的字段)
 	fields     map[string]*modelField //sql字段信息key是sql字段名
+	columns    []string               //sql字段名，按模型中定义的顺序排列
+	autoIncr   string                 //标记了auto_increment的sql字段名
+	nested     []*nestedField         //嵌套的结构体字段
+	accessor   bool                   //是否使用生成的访问器读写字段
 }
 
 //where条件结构
 type whereCond struct {
-	union    string      //连接符AND/OR
-	field    string      //字段
-	operator string      //运算符
-	value    interface{} //字段值
+	union    string       //连接符AND/OR
+	field    string       //字段
+	operator string       //运算符
+	value    interface{}  //字段值
+	raw      *rawExpr     //原生条件语句
+	group    bool         //是否是条件组
+	children []*whereCond //条件组中的条件
+	not      bool         //条件组是否取反
 }
 
+//字段名，作为条件的值时表示与另一个字段比较
+type columnName string
+
+//用反斜杠转义了通配符的LIKE匹配模式，构建时会用ESCAPE声明转义符
+type likePattern string
+
 //模型里的字段信息
 type modelField struct {
 	VarName string //模型变量名
 	VarType string //模型变量类型
 	SQLName string //数据表字段名
 	//SQLType string //数据表字段类型
+	index    []int //字段在结构体中的索引，用于FieldByIndex
+	autoIncr bool  //是否标记了auto_increment
+}
+
+//模型里嵌套的结构体字段，用于将JOIN查询的结果赋值到子结构体
+type nestedField struct {
+	prefix string       //结果集中字段的前缀，即标记的值
+	index  []int        //字段在结构体中的索引
+	isPtr  bool         //是否是结构体指针
+	rType  reflect.Type //结构体的reflectType
+	info   *modelInfo   //结构体的模型信息
+}
+
+//JOIN子句
+type join struct {
+	kind  string //连接方式：INNER JOIN/LEFT JOIN/RIGHT JOIN
+	table string //表名
+	alias string //别名
+	on    string //连接条件
+}
+
+//ON DUPLICATE KEY UPDATE子句中要更新的字段
+type duplicateSet struct {
+	field string      //字段
+	mode  string      //更新方式：VALUES/SET/EXPR/INCR
+	value interface{} //SET时的值，EXPR时的表达式，INCR时的增量
+}
+
+//公用表表达式
+type cte struct {
+	name    string   //名称
+	columns []string //字段名，为空则使用查询结果的字段名
+	sess    *Session //定义公用表表达式的SELECT会话
+}
+
+//UNION子句
+type union struct {
+	all  bool     //是否是UNION ALL
+	sess *Session //要合并的SELECT会话
+}
+
+//索引提示
+type indexHint struct {
+	kind  string   //提示方式：USE/FORCE/IGNORE
+	names []string //索引名
+}
+
+//游标分页
+type keyset struct {
+	cursor string        //After()或Before()传入的游标
+	before bool          //是否是查询游标之前的记录
+	values []interface{} //从游标中解析出的排序字段的值，与ORDER BY的字段依次对应
+}
+
+//锁定读子句
+type lockClause struct {
+	mode   string   //锁定方式：FOR UPDATE/FOR SHARE/LOCK IN SHARE MODE
+	tables []string //OF后面的表名或别名，只锁定这些表的记录
+	wait   string   //遇到已锁定的记录时的处理方式：NOWAIT/SKIP LOCKED
 }
 
 //排序规则
 type orderBy struct {
 	field     string
 	direction string
+	raw       *rawExpr //OrderByRaw()传入的原生排序语句
+}
+
+//带有?占位符和参数的原生语句片段
+type rawExpr struct {
+	stmt string        //原生语句
+	args []interface{} //与?占位符依次对应的参数
 }
@@ -0,0 +1,89 @@
+package mysqlib
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"time"
+)
+
+// ErrNoChanges Track()跟踪的模型没有被修改的字段，UPDATE会话没有要更新的内容
+var ErrNoChanges = errors.New("模型没有被修改的字段")
+
+// Track 保存模型当前的值作为快照，m必须是结构体的内存地址，通常在查询出记录后调用
+// 之后用Update(m)构建语句且没有用Column()指定字段时，只更新与快照相比被修改了的字段
+// UPDATE语句构建成功后快照被丢弃，构建失败时快照仍然保留；跟踪后不再更新的模型需要用Untrack(m)丢弃快照
+func (instance *Instance) Track(m interface{}) error {
+	rValue := reflect.ValueOf(m)
+	if rValue.Kind() != reflect.Ptr || rValue.IsNil() == true || rValue.Elem().Kind() != reflect.Struct {
+		return errors.New("Track()的参数必须是结构体的内存地址")
+	}
+	info := instance.getModelInfo(rValue.Elem().Type())
+	snapshot := make(map[string]interface{}, len(info.columns))
+	for _, column := range info.columns {
+		value := rValue.Elem().FieldByIndex(info.fields[column].index).Interface()
+		//[]byte需要复制，否则修改模型时快照也会被修改
+		if b, ok := value.([]byte); ok == true && b != nil {
+			value = append([]byte{}, b...)
+		}
+		snapshot[column] = value
+	}
+
+	instance.trackMu.Lock()
+	defer instance.trackMu.Unlock()
+	if instance.tracked == nil {
+		instance.tracked = make(map[interface{}]map[string]interface{})
+	}
+	instance.tracked[m] = snapshot
+	return nil
+}
+
+// Untrack 丢弃Track()保存的模型快照
+func (instance *Instance) Untrack(m interface{}) {
+	if isPtr(m) == false {
+		return
+	}
+	instance.trackMu.Lock()
+	defer instance.trackMu.Unlock()
+	delete(instance.tracked, m)
+}
+
+//读取模型快照，没有快照时返回nil
+func (instance *Instance) snapshotOf(m interface{}) map[string]interface{} {
+	if isPtr(m) == false {
+		return nil
+	}
+	instance.trackMu.Lock()
+	defer instance.trackMu.Unlock()
+	return instance.tracked[m]
+}
+
+//m是否是指针，只有指针才能作为快照的key，其它类型的值可能无法比较
+func isPtr(m interface{}) bool {
+	return m != nil && reflect.TypeOf(m).Kind() == reflect.Ptr
+}
+
+//与快照相比被修改了的字段，按模型中字段的顺序排列
+func (sess *Session) changedColumns() []string {
+	var columns []string
+	values := sess.rowValues(sess.modelValue.rValue, sess.modelInfo.columns)
+	for k, column := range sess.modelInfo.columns {
+		if valueEqual(sess.snapshot[column], values[k]) == false {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+//比较字段的值是否相同
+func valueEqual(a, b interface{}) bool {
+	switch v := a.(type) {
+	case time.Time:
+		t, ok := b.(time.Time)
+		return ok == true && v.Equal(t) == true
+	case []byte:
+		t, ok := b.([]byte)
+		return ok == true && (v == nil) == (t == nil) && bytes.Equal(v, t) == true
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,72 @@
+package mysqlib
+
+import (
+	"errors"
+	"reflect"
+)
+
+//树形结构的表使用的字段名和公用表表达式名称
+const (
+	treeIDColumn     = "id"        //记录编号
+	treeParentColumn = "parent_id" //上级记录编号
+	treeDepthColumn  = "depth"     //相对于起始记录的层级
+	treeName         = "tree"      //递归公用表表达式的名称
+)
+
+// Ancestors 构建查询树形结构的表中一条记录所有上级记录的SELECT会话，按层级由近到远排序
+// 模型必须有标记为id和parent_id的字段，m必须是模型slice的内存地址，id是起始记录的编号
+// maxDepth是最多查询的层数，<=0时不限制（仍受MySQL的cte_max_recursion_depth限制）
+// 返回的会话可以继续添加条件、分页等，再用Build()构建，用ScanModelSlice()赋值
+func (instance *Instance) Ancestors(m interface{}, id interface{}, maxDepth int) *Session {
+	return instance.treeHandle(m, id, maxDepth, "c."+treeIDColumn+" = t."+treeParentColumn)
+}
+
+// Descendants 构建查询树形结构的表中一条记录所有下级记录的SELECT会话，按层级由近到远排序，用法与Ancestors()相同
+func (instance *Instance) Descendants(m interface{}, id interface{}, maxDepth int) *Session {
+	return instance.treeHandle(m, id, maxDepth, "c."+treeParentColumn+" = t."+treeIDColumn)
+}
+
+//构建递归查询，on是递归查询的表与上一层结果的连接条件
+func (instance *Instance) treeHandle(m interface{}, id interface{}, maxDepth int, on string) *Session {
+	sess := instance.Select(m)
+	rType := reflect.TypeOf(m)
+	if rType == nil || rType.Kind() != reflect.Ptr || rType.Elem().Kind() != reflect.Slice || rType.Elem().Elem().Kind() != reflect.Struct {
+		sess.err = errors.New("树形查询的模型必须是结构体slice的内存地址")
+		return sess
+	}
+	info := instance.getModelInfo(rType.Elem().Elem())
+	if _, ok := info.fields[treeIDColumn]; ok == false {
+		sess.err = errors.New("树形查询的模型必须有标记为`" + treeIDColumn + "`的字段")
+		return sess
+	}
+	if _, ok := info.fields[treeParentColumn]; ok == false {
+		sess.err = errors.New("树形查询的模型必须有标记为`" + treeParentColumn + "`的字段")
+		return sess
+	}
+
+	//起始记录，层级为0
+	anchor := instance.Select(m).
+		Column(info.columns...).
+		ColumnRaw("0", treeDepthColumn).
+		Where(treeIDColumn, "=", id)
+
+	//逐层查询上级或下级记录，层级加1
+	columns := make([]string, len(info.columns))
+	for k, v := range info.columns {
+		columns[k] = "c." + v
+	}
+	recursive := instance.Select(m).
+		As("c").
+		Column(columns...).
+		ColumnRaw("`t`.`"+treeDepthColumn+"`+1", treeDepthColumn).
+		Join(treeName, "t", on)
+	if maxDepth > 0 {
+		recursive.Where("t."+treeDepthColumn, "<", maxDepth)
+	}
+
+	//从公用表表达式中查询，排除起始记录
+	return sess.WithRecursive(treeName, anchor.UnionAll(recursive)).
+		Table(treeName).
+		Where(treeDepthColumn, ">", 0).
+		OrderBy(treeDepthColumn, "ASC")
+}
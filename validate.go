@@ -0,0 +1,235 @@
+package mysqlib
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+//SET_VAR优化器提示中变量名和值的格式
+var hintPattern = regexp.MustCompile(`^[\w.]+$`)
+
+//允许在条件中使用的运算符
+var operators = map[string]bool{
+	"=":           true,
+	"<>":          true,
+	"!=":          true,
+	"<":           true,
+	"<=":          true,
+	">":           true,
+	">=":          true,
+	"<=>":         true,
+	"LIKE":        true,
+	"NOT LIKE":    true,
+	"REGEXP":      true,
+	"NOT REGEXP":  true,
+	"RLIKE":       true,
+	"NOT RLIKE":   true,
+	"IN":          true,
+	"NOT IN":      true,
+	"BETWEEN":     true,
+	"NOT BETWEEN": true,
+	"IS NULL":     true,
+	"IS NOT NULL": true,
+	"EXISTS":      true,
+	"NOT EXISTS":  true,
+}
+
+//检查标识符（表名、字段名、别名），不能为空，不能包含反引号和空字符
+func checkIdentifier(name string) error {
+	if name == "" || strings.ContainsAny(name, "`\x00") {
+		return errors.New("标识符`" + strings.Replace(name, "`", "", -1) + "`不合法，不能为空，也不能包含反引号")
+	}
+	return nil
+}
+
+//检查字段名，可以带有表名或别名，例如u.id，也可以是*或u.*
+func checkField(field string) error {
+	for _, v := range strings.Split(field, ".") {
+		if v == "*" {
+			continue
+		}
+		if err := checkIdentifier(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//检查原生语句中?占位符的数量与参数数量是否一致，以及引号和多行注释是否都已结束
+func checkRaw(raw *rawExpr) error {
+	parts, open := scanRaw(raw.stmt)
+	//没有结束的引号或多行注释会吞掉后面拼接的语句
+	if open != 0 && open != '\n' {
+		return errors.New("原生语句`" + strings.Replace(raw.stmt, "`", "", -1) + "`中有没有结束的引号或注释")
+	}
+	if len(parts)-1 != len(raw.args) {
+		return errors.New("原生语句`" + strings.Replace(raw.stmt, "`", "", -1) + "`中的占位符数量与参数数量不一致")
+	}
+	return nil
+}
+
+//检查WHERE/HAVING条件中的字段名和运算符
+func checkConds(conds []*whereCond) error {
+	for _, cond := range conds {
+		//原生条件语句
+		if cond.raw != nil {
+			if err := checkRaw(cond.raw); err != nil {
+				return err
+			}
+			continue
+		}
+		//条件组
+		if cond.group == true {
+			if err := checkConds(cond.children); err != nil {
+				return err
+			}
+			continue
+		}
+		if operators[cond.operator] == false {
+			return errors.New("不支持的运算符`" + strings.Replace(cond.operator, "`", "", -1) + "`")
+		}
+		switch cond.operator {
+		case "EXISTS", "NOT EXISTS":
+			if sub, ok := cond.value.(*Session); ok == false || sub == nil {
+				return errors.New("`" + cond.operator + "`条件的值必须是SELECT会话")
+			}
+			continue
+		case "BETWEEN", "NOT BETWEEN":
+			if len(inValues(cond.value)) != 2 {
+				return errors.New("`" + cond.operator + "`条件的值必须是最小值和最大值")
+			}
+		}
+		if err := checkField(cond.field); err != nil {
+			return err
+		}
+		if column, ok := cond.value.(columnName); ok == true {
+			if err := checkField(string(column)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+//检查会话中所有会拼接到语句中的标识符和运算符，避免通过字段名、排序字段等注入SQL
+func (sess *Session) validate() error {
+	if err := checkIdentifier(sess.tableName); err != nil {
+		return err
+	}
+	if sess.alias != "" {
+		if err := checkIdentifier(sess.alias); err != nil {
+			return err
+		}
+	}
+	for _, v := range sess.stmt.field {
+		if err := checkField(v.key); err != nil {
+			return err
+		}
+		if raw, ok := v.value.(*rawExpr); ok == true {
+			if err := checkRaw(raw); err != nil {
+				return err
+			}
+		}
+	}
+	//INSERT/UPDATE从模型中读取Column()指定的字段的值，字段必须在模型中存在，INSERT ... SELECT的值来自SELECT会话
+	if (sess.stmt.action == "INSERT" || sess.stmt.action == "REPLACE" || sess.stmt.action == "UPDATE") && sess.stmt.fromSelect == nil {
+		for _, v := range sess.stmt.field {
+			if _, ok := sess.modelInfo.fields[v.key]; ok == false {
+				return errors.New("字段`" + v.key + "`在模型中不存在")
+			}
+		}
+	}
+	for _, v := range sess.stmt.addValue {
+		if err := checkIdentifier(v.key); err != nil {
+			return err
+		}
+		if raw, ok := v.value.(*rawExpr); ok == true {
+			if err := checkRaw(raw); err != nil {
+				return err
+			}
+		}
+	}
+	for _, v := range sess.stmt.joins {
+		if err := checkIdentifier(v.table); err != nil {
+			return err
+		}
+		if v.alias != "" {
+			if err := checkIdentifier(v.alias); err != nil {
+				return err
+			}
+		}
+	}
+	if err := checkConds(sess.stmt.where.conds); err != nil {
+		return err
+	}
+	if err := checkConds(sess.stmt.having); err != nil {
+		return err
+	}
+	for _, v := range sess.stmt.groups {
+		if err := checkField(v); err != nil {
+			return err
+		}
+	}
+	for _, v := range sess.stmt.orders {
+		if v.raw != nil {
+			if err := checkRaw(v.raw); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := checkField(v.field); err != nil {
+			return err
+		}
+	}
+	for _, v := range sess.stmt.duplicate {
+		if err := checkIdentifier(v.field); err != nil {
+			return err
+		}
+	}
+	if sess.stmt.lock.wait != "" && sess.stmt.lock.mode == "" {
+		return errors.New("`NoWait()`和`SkipLocked()`必须与`ForUpdate()`或`ForShare()`同时使用")
+	}
+	if sess.stmt.lock.wait != "" && sess.stmt.lock.mode == "LOCK IN SHARE MODE" {
+		return errors.New("`LockInShareMode()`不能与`NoWait()`和`SkipLocked()`同时使用")
+	}
+	for _, v := range sess.stmt.lock.tables {
+		if err := checkIdentifier(v); err != nil {
+			return err
+		}
+	}
+	for _, v := range sess.stmt.ctes {
+		if err := checkIdentifier(v.name); err != nil {
+			return err
+		}
+		for _, column := range v.columns {
+			if err := checkIdentifier(column); err != nil {
+				return err
+			}
+		}
+	}
+	for _, v := range sess.stmt.indexHints {
+		for _, name := range v.names {
+			if err := checkIdentifier(name); err != nil {
+				return err
+			}
+		}
+	}
+	if len(sess.stmt.indexHints) > 0 && sess.stmt.action == "DELETE" {
+		if len(sess.stmt.orders) > 0 || sess.stmt.limit > 0 {
+			return errors.New("`DELETE`使用索引提示时不能同时使用`OrderBy()`和`Limit()`")
+		}
+	}
+	//优化器提示中不能出现注释结束符
+	for _, v := range sess.stmt.hints {
+		if strings.Contains(v, "*/") == true {
+			return errors.New("优化器提示不能包含`*/`")
+		}
+	}
+	if sess.stmt.rowAlias != "" {
+		if err := checkIdentifier(sess.stmt.rowAlias); err != nil {
+			return err
+		}
+	}
+	return nil
+}